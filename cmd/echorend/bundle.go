@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/BlindGarret/echorend/gatherers/bundle"
+)
+
+func bundleTemplates(args []string, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("bundle", stderr)
+	output := fs.String("o", "templates.bundle.json", "file to write the bundle to, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	renderer, err := opts.renderer()
+	if err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitUsage
+	}
+	if err := renderer.Setup(); err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitProblems
+	}
+	b, err := renderer.Bundle()
+	if err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitProblems
+	}
+
+	if *output == "-" {
+		err = bundle.Write(stdout, b)
+	} else {
+		err = writeBundleFile(*output, b)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "echorend: writing bundle: %v\n", err)
+		return exitProblems
+	}
+	return exitOK
+}
+
+func writeBundleFile(path string, b *bundle.Bundle) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := bundle.Write(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/glob"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/BlindGarret/echorend/renderers/handlebars/helpers"
+)
+
+// options are the flags every command shares, describing where templates are and how the renderer is set up.
+type options struct {
+	views      string
+	partials   string
+	layouts    string
+	layout     string
+	extensions string
+	tld        bool
+	collisions string
+	helpers    bool
+}
+
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts := &options{}
+	fs.StringVar(&opts.views, "views", "templates/views", "directory of view templates")
+	fs.StringVar(&opts.partials, "partials", "templates/partials", "directory of partial templates, empty for none")
+	fs.StringVar(&opts.layouts, "layouts", "", "directory of layout templates, empty for none")
+	fs.StringVar(&opts.layout, "layout", "", "default layout views are rendered in")
+	fs.StringVar(&opts.extensions, "ext", ".hbs", "comma separated template file extensions")
+	fs.BoolVar(&opts.tld, "tld", false, "include the template directory in template names")
+	fs.StringVar(&opts.collisions, "collisions", "fail", "template name collision policy: fail, first or last")
+	fs.BoolVar(&opts.helpers, "helpers", false, "register the bundled helpers, as helpers.All() does")
+	return fs, opts
+}
+
+// renderer builds the Handlebars renderer the options describe, without setting it up.
+func (o *options) renderer() (*handlebars.HandlebarsRenderer, error) {
+	policy, err := collisionPolicy(o.collisions)
+	if err != nil {
+		return nil, err
+	}
+	config := handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    o.gatherer(o.views, policy),
+		PartialGatherer: o.gatherer(o.partials, policy),
+		LayoutGatherer:  o.gatherer(o.layouts, policy),
+		DefaultLayout:   o.layout,
+		CollisionPolicy: policy,
+	}
+	// the CLI can't load an application's own helpers, only those bundled with echorend
+	if o.helpers {
+		config.Helpers = helpers.All()
+	}
+	return handlebars.NewHandlebarsRendererWithConfig(config), nil
+}
+
+func (o *options) gatherer(dir string, policy echorend.CollisionPolicy) echorend.RawTemplateGatherer {
+	if dir == "" {
+		return nil
+	}
+	return glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir:     &dir,
+		IncludeTLDInKey: o.tld,
+		Extensions:      splitList(o.extensions),
+		CollisionPolicy: policy,
+	})
+}
+
+func collisionPolicy(name string) (echorend.CollisionPolicy, error) {
+	switch name {
+	case "fail":
+		return echorend.CollisionFail, nil
+	case "first":
+		return echorend.CollisionFirstWins, nil
+	case "last":
+		return echorend.CollisionLastWins, nil
+	default:
+		return 0, fmt.Errorf("unknown collision policy %q, want fail, first or last", name)
+	}
+}
+
+func splitList(s string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BlindGarret/echorend"
+)
+
+// problem is a single lint finding, located as precisely as the error behind it allows.
+type problem struct {
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Template string `json:"template,omitempty"`
+	Message  string `json:"message"`
+}
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+func lint(args []string, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("lint", stderr)
+	format := fs.String("format", "text", "output format: text, json or github")
+	strict := fs.Bool("strict", false, "treat warnings, such as unused partials, as errors")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" && *format != "github" {
+		fmt.Fprintf(stderr, "echorend: unknown format %q, want text, json or github\n", *format)
+		return exitUsage
+	}
+	renderer, err := opts.renderer()
+	if err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitUsage
+	}
+
+	problems := make([]problem, 0)
+	if err := renderer.Setup(); err != nil {
+		// templates which parsed are still checked for missing partials, so a parse error doesn't hide them
+		problems = append(problems, problemsFrom(err, severityError)...)
+		for _, err := range renderer.CheckPartials() {
+			problems = append(problems, problemsFrom(err, severityError)...)
+		}
+	} else {
+		for _, err := range renderer.CheckRenders() {
+			problems = append(problems, problemsFrom(err, severityError)...)
+		}
+		warning := severityWarning
+		if *strict {
+			warning = severityError
+		}
+		for _, name := range renderer.UnusedPartials() {
+			problems = append(problems, problem{Severity: warning, Template: name, Message: fmt.Sprintf("partial %s is never used", name)})
+		}
+	}
+
+	problems = dedupe(problems)
+	sortProblems(problems)
+	if err := writeProblems(stdout, *format, problems); err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitProblems
+	}
+	errs := 0
+	for _, p := range problems {
+		if p.Severity == severityError {
+			errs++
+		}
+	}
+	if *format == "text" {
+		fmt.Fprintf(stderr, "%d error(s), %d warning(s)\n", errs, len(problems)-errs)
+	}
+	if errs > 0 {
+		return exitProblems
+	}
+	return exitOK
+}
+
+// problemsFrom flattens setup and collision errors into one problem per template failure or collision.
+func problemsFrom(err error, severity string) []problem {
+	var setupErr *echorend.SetupError
+	if errors.As(err, &setupErr) {
+		problems := make([]problem, 0)
+		for _, err := range setupErr.Errors {
+			problems = append(problems, problemsFrom(err, severity)...)
+		}
+		return problems
+	}
+
+	var collisionErr *echorend.CollisionError
+	if errors.As(err, &collisionErr) {
+		problems := make([]problem, 0, len(collisionErr.Collisions))
+		for _, collision := range collisionErr.Collisions {
+			p := problem{
+				Severity: severity,
+				Template: collision.TemplateName,
+				Message:  fmt.Sprintf("template %s is defined by %s", collision.TemplateName, strings.Join(collision.Sources, ", ")),
+			}
+			if len(collision.Sources) > 0 {
+				p.File = collision.Sources[0]
+			}
+			problems = append(problems, p)
+		}
+		return problems
+	}
+
+	var templateErr *echorend.TemplateError
+	if errors.As(err, &templateErr) {
+		return []problem{{
+			Severity: severity,
+			File:     templateErr.Source,
+			Line:     templateErr.Line,
+			Column:   templateErr.Column,
+			Template: templateErr.TemplateName,
+			Message:  templateErr.Err.Error(),
+		}}
+	}
+
+	return []problem{{Severity: severity, Message: err.Error()}}
+}
+
+// dedupe drops repeated problems, such as a collision both the gatherer and the renderer report.
+func dedupe(problems []problem) []problem {
+	seen := make(map[problem]bool)
+	unique := make([]problem, 0, len(problems))
+	for _, p := range problems {
+		if !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
+
+// sortProblems orders problems by location, keeping those without one last, so output is stable between runs.
+func sortProblems(problems []problem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if (a.File == "") != (b.File == "") {
+			return a.File != ""
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+func writeProblems(w io.Writer, format string, problems []problem) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(problems)
+	case "github":
+		for _, p := range problems {
+			fmt.Fprintf(w, "::%s %s::%s\n", p.Severity, githubProperties(p), githubEscapeData(p.Message))
+		}
+	default:
+		for _, p := range problems {
+			location := (&echorend.TemplateError{Source: p.File, Line: p.Line, Column: p.Column}).Location()
+			if location != "" {
+				location += ": "
+			}
+			fmt.Fprintf(w, "%s%s: %s\n", location, p.Severity, p.Message)
+		}
+	}
+	return nil
+}
+
+// githubProperties formats the location of a problem for a GitHub Actions workflow command.
+func githubProperties(p problem) string {
+	properties := make([]string, 0, 4)
+	if p.File != "" {
+		properties = append(properties, "file="+githubEscapeProperty(p.File))
+	}
+	if p.Line > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", p.Line))
+	}
+	if p.Column > 0 {
+		properties = append(properties, fmt.Sprintf("col=%d", p.Column))
+	}
+	if p.Template != "" {
+		properties = append(properties, "title="+githubEscapeProperty(p.Template))
+	}
+	return strings.Join(properties, ",")
+}
+
+// githubEscapeData escapes a workflow command's message, where GitHub only decodes %, CR and LF.
+func githubEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubEscapeProperty escapes a workflow command's property value, which can't hold the : and , separating them.
+func githubEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
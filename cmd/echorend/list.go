@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+func list(args []string, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("list", stderr)
+	asJSON := fs.Bool("json", false, "print the templates as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	renderer, err := opts.renderer()
+	if err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitUsage
+	}
+	if err := renderer.Setup(); err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitProblems
+	}
+
+	templates := renderer.Templates()
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(templates); err != nil {
+			fmt.Fprintf(stderr, "echorend: %v\n", err)
+			return exitProblems
+		}
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ROLE\tNAME\tSOURCE")
+	for _, template := range templates {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", template.Role, template.Name, template.Source)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitProblems
+	}
+	return exitOK
+}
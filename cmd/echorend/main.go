@@ -0,0 +1,63 @@
+// Command echorend checks, lists and renders templates outside of a server, using the same glob gatherers and
+// Handlebars renderer setup, so template problems can be caught in CI before deploying.
+//
+// Usage:
+//
+//	echorend lint [flags]
+//	echorend list [flags]
+//	echorend render [flags] <template>
+//	echorend bundle [flags]
+//
+// Run echorend <command> -h for the flags of each command. Templates using the bundled helpers need -helpers. An
+// application's own helpers can't be loaded by the CLI, so lint reports calls to them as unknown helpers.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	exitOK       = 0
+	exitProblems = 1
+	exitUsage    = 2
+)
+
+const usage = `usage: echorend <command> [flags]
+
+commands:
+  lint    report parse errors, missing partials, unknown helpers and name collisions
+  list    print the resolved template names and their sources
+  render  render a template with JSON data from a file or stdin
+  bundle  write the templates to a bundle file, for loading without the filesystem
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command named by the first argument, returning the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "lint":
+		return lint(args[1:], stdout, stderr)
+	case "list":
+		return list(args[1:], stdout, stderr)
+	case "render":
+		return render(args[1:], stdin, stdout, stderr)
+	case "bundle":
+		return bundleTemplates(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "echorend: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
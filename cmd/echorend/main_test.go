@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend/gatherers/bundle"
+)
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	code := run(args, strings.NewReader(stdin), stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+var goodFlags = []string{"-views", "testdata/good/views", "-partials", "testdata/good/partials", "-layouts", "testdata/good/layouts"}
+
+var badFlags = []string{"-views", "testdata/bad/views", "-partials", "testdata/bad/partials", "-ext", ".hbs,.handlebars"}
+
+func TestRun_NoCommand_ExitsWithUsage(t *testing.T) {
+	code, _, stderr := runCommand("")
+
+	if code != exitUsage {
+		t.Errorf("Expected exit code %d, got %d", exitUsage, code)
+	}
+	if !strings.Contains(stderr, "usage:") {
+		t.Errorf("Expected usage, got %q", stderr)
+	}
+}
+
+func TestRun_UnknownCommand_ExitsWithUsage(t *testing.T) {
+	code, _, _ := runCommand("", "build")
+
+	if code != exitUsage {
+		t.Errorf("Expected exit code %d, got %d", exitUsage, code)
+	}
+}
+
+func TestLint_GoodTemplates_ExitsZeroWithUnusedPartialWarning(t *testing.T) {
+	code, stdout, _ := runCommand("", append([]string{"lint"}, goodFlags...)...)
+
+	if code != exitOK {
+		t.Errorf("Expected exit code %d, got %d: %s", exitOK, code, stdout)
+	}
+	if stdout != "warning: partial spare is never used\n" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+}
+
+func TestLint_Strict_FailsOnWarnings(t *testing.T) {
+	code, _, _ := runCommand("", append([]string{"lint", "-strict"}, goodFlags...)...)
+
+	if code != exitProblems {
+		t.Errorf("Expected exit code %d, got %d", exitProblems, code)
+	}
+}
+
+func TestLint_BadTemplates_ReportsEveryProblem(t *testing.T) {
+	code, stdout, _ := runCommand("", append([]string{"lint", "-format", "json"}, badFlags...)...)
+
+	if code != exitProblems {
+		t.Errorf("Expected exit code %d, got %d", exitProblems, code)
+	}
+	var problems []problem
+	if err := json.Unmarshal([]byte(stdout), &problems); err != nil {
+		t.Fatalf("Expected JSON output, got %v: %s", err, stdout)
+	}
+	messages := make([]string, 0)
+	for _, p := range problems {
+		messages = append(messages, p.Message)
+	}
+	if len(problems) != 3 {
+		t.Fatalf("Expected a parse error, a collision and a missing partial, got %v", messages)
+	}
+	for _, p := range problems {
+		if p.File == "" || p.Severity != severityError {
+			t.Errorf("Expected a located error, got %+v", p)
+		}
+	}
+}
+
+func TestLint_MissingPartial_ReportsFileAndLine(t *testing.T) {
+	code, stdout, _ := runCommand("", "lint", "-views", "testdata/bad/views", "-partials", "testdata/bad/partials", "-ext", ".hbs", "-collisions", "first")
+
+	if code != exitProblems {
+		t.Errorf("Expected exit code %d, got %d", exitProblems, code)
+	}
+	if !strings.Contains(stdout, "testdata/bad/views/broken.hbs") {
+		t.Errorf("Expected the parse error, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "testdata/bad/views/page.hbs:2: error: partial missing not found\n") {
+		t.Errorf("Expected the missing partial despite the parse error, got %q", stdout)
+	}
+}
+
+func TestLint_GithubFormat_WritesWorkflowCommands(t *testing.T) {
+	code, stdout, _ := runCommand("", "lint", "-format", "github", "-views", "testdata/bad/views", "-partials", "testdata/bad/partials", "-ext", ".hbs")
+
+	if code != exitProblems {
+		t.Errorf("Expected exit code %d, got %d", exitProblems, code)
+	}
+	if !strings.HasPrefix(stdout, "::error file=testdata/bad/views/broken.hbs,line=1") {
+		t.Errorf("Expected a GitHub error annotation, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "::Expecting OpenEndBlock, got: 'EOF'\n") {
+		t.Errorf("Expected the message unescaped, got %q", stdout)
+	}
+}
+
+func TestList_GoodTemplates_PrintsNamesAndSources(t *testing.T) {
+	code, stdout, _ := runCommand("", append([]string{"list"}, goodFlags...)...)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d", exitOK, code)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	expected := [][]string{
+		{"ROLE", "NAME", "SOURCE"},
+		{"view", "index", "testdata/good/views/index.hbs"},
+		{"partial", "item", "testdata/good/partials/item.hbs"},
+		{"partial", "spare", "testdata/good/partials/spare.hbs"},
+		{"layout", "main", "testdata/good/layouts/main.hbs"},
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), stdout)
+	}
+	for i, line := range lines {
+		if strings.Join(strings.Fields(line), " ") != strings.Join(expected[i], " ") {
+			t.Errorf("Expected line %v, got %q", expected[i], line)
+		}
+	}
+}
+
+func TestRender_DataFromStdin_RendersInLayout(t *testing.T) {
+	args := append([]string{"render", "-data", "-", "-layout", "main"}, goodFlags...)
+	code, stdout, stderr := runCommand(`{"title": "Hello", "name": "World"}`, append(args, "index")...)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if stdout != "<main><h1>Hello</h1><p>World</p></main>" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+}
+
+func TestRender_DataFromFile_Renders(t *testing.T) {
+	args := append([]string{"render", "-data", "testdata/data.json"}, goodFlags...)
+	code, stdout, stderr := runCommand("", append(args, "item")...)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if stdout != "<p>From a file</p>" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+}
+
+func TestRender_UnknownTemplate_ExitsNonZero(t *testing.T) {
+	code, _, stderr := runCommand("", append(append([]string{"render"}, goodFlags...), "missing")...)
+
+	if code != exitProblems {
+		t.Errorf("Expected exit code %d, got %d", exitProblems, code)
+	}
+	if !strings.Contains(stderr, "template missing not found") {
+		t.Errorf("Unexpected error %q", stderr)
+	}
+}
+
+func TestRender_InvalidData_ExitsNonZero(t *testing.T) {
+	code, _, _ := runCommand("{", append(append([]string{"render", "-data", "-"}, goodFlags...), "index")...)
+
+	if code != exitProblems {
+		t.Errorf("Expected exit code %d, got %d", exitProblems, code)
+	}
+}
+
+func TestBundle_GoodTemplates_WritesLoadableBundle(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "templates.bundle.json")
+	code, _, stderr := runCommand("", append([]string{"bundle", "-o", output, "-layout", "main"}, goodFlags...)...)
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	b, err := bundle.Load(output)
+	if err != nil {
+		t.Fatalf("Expected a valid bundle, got %v", err)
+	}
+	if b.DefaultLayout != "main" || len(b.Templates) != 4 {
+		t.Errorf("Unexpected bundle %+v", b)
+	}
+}
+
+func TestBundle_BadTemplates_ExitsNonZero(t *testing.T) {
+	code, stdout, _ := runCommand("", append([]string{"bundle", "-o", "-"}, badFlags...)...)
+
+	if code != exitProblems {
+		t.Errorf("Expected exit code %d, got %d", exitProblems, code)
+	}
+	if stdout != "" {
+		t.Errorf("Expected no bundle written, got %q", stdout)
+	}
+}
+
+func TestLint_BundledHelpers_AreOnlyKnownWithHelpersFlag(t *testing.T) {
+	args := []string{"lint", "-views", "testdata/helpers/views", "-partials", ""}
+	withoutCode, withoutOut, _ := runCommand("", args...)
+	withCode, withOut, _ := runCommand("", append(args, "-helpers")...)
+
+	if withoutCode != exitProblems || !strings.Contains(withoutOut, "helper strUpper not found") {
+		t.Errorf("Expected strUpper to be unknown without -helpers, got %d: %q", withoutCode, withoutOut)
+	}
+	if withCode != exitOK || withOut != "" {
+		t.Errorf("Expected no problems with -helpers, got %d: %q", withCode, withOut)
+	}
+}
+
+func TestRender_HelpersFlag_RendersWithBundledHelpers(t *testing.T) {
+	code, stdout, stderr := runCommand(`{"name": "ada"}`, "render", "-views", "testdata/helpers/views", "-partials", "", "-helpers", "-data", "-", "index")
+
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if stdout != "<p>ADA</p>" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+func render(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs, opts := newFlagSet("render", stderr)
+	dataPath := fs.String("data", "", "JSON file of data to render with, - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: echorend render [flags] <template>")
+		return exitUsage
+	}
+	renderer, err := opts.renderer()
+	if err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitUsage
+	}
+
+	data, err := readData(*dataPath, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "echorend: reading data: %v\n", err)
+		return exitProblems
+	}
+	if err := renderer.Setup(); err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitProblems
+	}
+	if err := renderer.Render(stdout, fs.Arg(0), data, nil); err != nil {
+		fmt.Fprintf(stderr, "echorend: %v\n", err)
+		return exitProblems
+	}
+	return exitOK
+}
+
+// readData decodes the JSON data at path, or stdin for -. Without a path the template is rendered with no data.
+func readData(path string, stdin io.Reader) (interface{}, error) {
+	var r io.Reader
+	switch path {
+	case "":
+		return nil, nil
+	case "-":
+		r = stdin
+	default:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var data interface{}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,83 @@
+package echorend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CollisionPolicy decides what happens when two templates resolve to the same name.
+type CollisionPolicy int
+
+const (
+	// CollisionFail reports every collision as a *CollisionError. It is the default policy.
+	CollisionFail CollisionPolicy = iota
+	// CollisionFirstWins keeps the first template gathered with a name and drops the rest.
+	CollisionFirstWins
+	// CollisionLastWins keeps the last template gathered with a name and drops the rest.
+	CollisionLastWins
+)
+
+// Collision is a single template name claimed by more than one source.
+type Collision struct {
+	TemplateName string
+	Sources      []string
+}
+
+// CollisionError reports every template name collision found, rather than only the first.
+type CollisionError struct {
+	Collisions []Collision
+}
+
+func (e *CollisionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d template name collision(s):", len(e.Collisions))
+	for _, collision := range e.Collisions {
+		fmt.Fprintf(&b, "\n\t%s: %s", collision.TemplateName, strings.Join(collision.Sources, ", "))
+	}
+	return b.String()
+}
+
+// ResolveCollisions applies the policy to any templates sharing a name, keeping the order templates were first seen in.
+// With CollisionFail the templates are returned untouched along with a *CollisionError listing every collision.
+func ResolveCollisions(templates []RawTemplateData, policy CollisionPolicy) ([]RawTemplateData, error) {
+	indexes := make(map[string]int)
+	resolved := make([]RawTemplateData, 0, len(templates))
+	collisions := make([]Collision, 0)
+	collisionIndexes := make(map[string]int)
+
+	for _, template := range templates {
+		i, exists := indexes[template.TemplateName]
+		if !exists {
+			indexes[template.TemplateName] = len(resolved)
+			resolved = append(resolved, template)
+			continue
+		}
+
+		c, seen := collisionIndexes[template.TemplateName]
+		if !seen {
+			c = len(collisions)
+			collisionIndexes[template.TemplateName] = c
+			collisions = append(collisions, Collision{
+				TemplateName: template.TemplateName,
+				Sources:      []string{sourceOf(resolved[i])},
+			})
+		}
+		collisions[c].Sources = append(collisions[c].Sources, sourceOf(template))
+
+		if policy == CollisionLastWins {
+			resolved[i] = template
+		}
+	}
+
+	if policy == CollisionFail && len(collisions) > 0 {
+		return templates, &CollisionError{Collisions: collisions}
+	}
+	return resolved, nil
+}
+
+func sourceOf(template RawTemplateData) string {
+	if template.Source == "" {
+		return "unknown source"
+	}
+	return template.Source
+}
@@ -0,0 +1,76 @@
+package echorend_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+)
+
+func collidingTemplates() []echorend.RawTemplateData {
+	return []echorend.RawTemplateData{
+		{TemplateName: "index", TemplateData: "first", Source: "views/index.hbs"},
+		{TemplateName: "about", TemplateData: "about", Source: "views/about.hbs"},
+		{TemplateName: "index", TemplateData: "second", Source: "views/index.handlebars"},
+		{TemplateName: "index", TemplateData: "third", Source: "views/index.html"},
+	}
+}
+
+func TestResolveCollisions_NoCollisions_ReturnsTemplates(t *testing.T) {
+	templates := []echorend.RawTemplateData{
+		{TemplateName: "index", Source: "views/index.hbs"},
+		{TemplateName: "about", Source: "views/about.hbs"},
+	}
+
+	resolved, err := echorend.ResolveCollisions(templates, echorend.CollisionFail)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resolved) != 2 {
+		t.Errorf("Expected 2 templates, got %d", len(resolved))
+	}
+}
+
+func TestResolveCollisions_FailPolicy_ReportsEverySource(t *testing.T) {
+	_, err := echorend.ResolveCollisions(collidingTemplates(), echorend.CollisionFail)
+
+	var collisionErr *echorend.CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("Expected CollisionError, got %v", err)
+	}
+	if len(collisionErr.Collisions) != 1 {
+		t.Fatalf("Expected 1 collision, got %d", len(collisionErr.Collisions))
+	}
+	collision := collisionErr.Collisions[0]
+	expected := []string{"views/index.hbs", "views/index.handlebars", "views/index.html"}
+	if collision.TemplateName != "index" || strings.Join(collision.Sources, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected index from %v, got %v", expected, collision)
+	}
+	if !strings.Contains(err.Error(), "views/index.handlebars") {
+		t.Errorf("Expected error message to list sources, got %s", err.Error())
+	}
+}
+
+func TestResolveCollisions_FirstWinsPolicy_KeepsFirst(t *testing.T) {
+	resolved, err := echorend.ResolveCollisions(collidingTemplates(), echorend.CollisionFirstWins)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resolved) != 2 || resolved[0].TemplateData != "first" || resolved[1].TemplateName != "about" {
+		t.Errorf("Expected first index then about, got %v", resolved)
+	}
+}
+
+func TestResolveCollisions_LastWinsPolicy_KeepsLast(t *testing.T) {
+	resolved, err := echorend.ResolveCollisions(collidingTemplates(), echorend.CollisionLastWins)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resolved) != 2 || resolved[0].TemplateData != "third" || resolved[1].TemplateName != "about" {
+		t.Errorf("Expected last index then about, got %v", resolved)
+	}
+}
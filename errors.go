@@ -0,0 +1,92 @@
+package echorend
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TemplateError is a failure in a single template, located as precisely as the template engine reports.
+type TemplateError struct {
+	TemplateName string
+	Source       string
+	// Line and Column are 1-based, and zero when the template engine doesn't report them.
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Location(), e.TemplateName, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// Location formats the source, line and column in the usual file:line:column form.
+func (e *TemplateError) Location() string {
+	location := e.Source
+	if e.Line > 0 {
+		location += fmt.Sprintf(":%d", e.Line)
+		if e.Column > 0 {
+			location += fmt.Sprintf(":%d", e.Column)
+		}
+	}
+	return location
+}
+
+// SetupError collects every error found while setting up a renderer, rather than stopping at the first.
+// Template failures are reported as *TemplateError, and errors.Is and errors.As see every collected error.
+type SetupError struct {
+	Errors []error
+}
+
+func (e *SetupError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d error(s) setting up templates:", len(e.Errors))
+	for _, err := range e.Errors {
+		lines := strings.Split(err.Error(), "\n")
+		fmt.Fprintf(&b, "\n  - %s", lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintf(&b, "\n    %s", line)
+		}
+	}
+	return b.String()
+}
+
+func (e *SetupError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any collected error matches target. errors.Is only follows Unwrap() []error from Go 1.20, so
+// this lets earlier versions see every collected error too.
+func (e *SetupError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first collected error matching target, for the same reason as Is.
+func (e *SetupError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateErrors returns just the template failures.
+func (e *SetupError) TemplateErrors() []*TemplateError {
+	templateErrs := make([]*TemplateError, 0)
+	for _, err := range e.Errors {
+		if templateErr, ok := err.(*TemplateError); ok {
+			templateErrs = append(templateErrs, templateErr)
+		}
+	}
+	return templateErrs
+}
@@ -0,0 +1,69 @@
+package echorend_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+)
+
+func TestTemplateError_Error_IncludesLocation(t *testing.T) {
+	err := &echorend.TemplateError{
+		TemplateName: "index",
+		Source:       "views/index.hbs",
+		Line:         3,
+		Column:       7,
+		Err:          errors.New("unexpected token"),
+	}
+
+	if err.Error() != "views/index.hbs:3:7: index: unexpected token" {
+		t.Errorf("Unexpected error message %s", err.Error())
+	}
+}
+
+func TestTemplateError_Error_OmitsUnknownPosition(t *testing.T) {
+	err := &echorend.TemplateError{
+		TemplateName: "index",
+		Source:       "views/index.hbs",
+		Err:          errors.New("unexpected token"),
+	}
+
+	if err.Error() != "views/index.hbs: index: unexpected token" {
+		t.Errorf("Unexpected error message %s", err.Error())
+	}
+}
+
+func TestSetupError_Error_IndentsEveryError(t *testing.T) {
+	err := &echorend.SetupError{Errors: []error{
+		errors.New("first\ndetail"),
+		errors.New("second"),
+	}}
+
+	expected := "2 error(s) setting up templates:\n  - first\n    detail\n  - second"
+	if err.Error() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, err.Error())
+	}
+}
+
+func TestSetupError_ErrorsIs_SeesEveryError(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	err := error(&echorend.SetupError{Errors: []error{first, &echorend.TemplateError{Err: second}}})
+
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("Expected errors.Is to see both errors")
+	}
+}
+
+func TestSetupError_IsAndAs_WalkEveryErrorWithoutUnwrap(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	templateErr := &echorend.TemplateError{TemplateName: "index", Err: second}
+	setupErr := &echorend.SetupError{Errors: []error{first, templateErr}}
+
+	var found *echorend.TemplateError
+	if !setupErr.Is(first) || !setupErr.Is(second) || setupErr.Is(errors.New("other")) {
+		t.Errorf("Expected Is to match only the collected errors")
+	}
+	if !setupErr.As(&found) || found != templateErr {
+		t.Errorf("Expected As to find the template error, got %v", found)
+	}
+}
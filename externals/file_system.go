@@ -1,6 +1,7 @@
 package externals
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -10,6 +11,12 @@ type FileAccess interface {
 	ReadFile(filename string) ([]byte, error)
 }
 
+// DirWalker is implemented by a FileAccess which can walk directory trees. Gatherers fall back to walking with
+// Glob when a FileAccess doesn't implement it.
+type DirWalker interface {
+	WalkDir(root string, fn fs.WalkDirFunc) error
+}
+
 type StdFileAccess struct {
 }
 
@@ -17,6 +24,10 @@ func (g *StdFileAccess) Glob(pattern string) ([]string, error) {
 	return filepath.Glob(pattern)
 }
 
+func (g *StdFileAccess) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, fn)
+}
+
 func (g *StdFileAccess) ReadFile(filename string) ([]byte, error) {
 	return os.ReadFile(filename)
 }
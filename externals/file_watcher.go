@@ -0,0 +1,107 @@
+package externals
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileWatcher starts watches over directories and everything below them.
+type FileWatcher interface {
+	Watch(dirs []string) (FileWatch, error)
+}
+
+// FileWatch is a running watch, reporting the paths of changed files until closed.
+type FileWatch interface {
+	Events() <-chan string
+	Errors() <-chan error
+	Close() error
+}
+
+type StdFileWatcher struct {
+}
+
+func (w *StdFileWatcher) Watch(dirs []string) (FileWatch, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	watch := &stdFileWatch{
+		watcher: watcher,
+		events:  make(chan string),
+		done:    make(chan struct{}),
+	}
+	for _, dir := range dirs {
+		if err := watch.addRecursive(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	go watch.run()
+	return watch, nil
+}
+
+type stdFileWatch struct {
+	watcher *fsnotify.Watcher
+	events  chan string
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (w *stdFileWatch) Events() <-chan string {
+	return w.events
+}
+
+func (w *stdFileWatch) Errors() <-chan error {
+	return w.watcher.Errors
+}
+
+func (w *stdFileWatch) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+	return err
+}
+
+func (w *stdFileWatch) run() {
+	defer close(w.events)
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) {
+				// fsnotify is not recursive, so directories created after the watch started need adding.
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = w.addRecursive(event.Name)
+				}
+			}
+			select {
+			case w.events <- event.Name:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *stdFileWatch) addRecursive(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return w.watcher.Add(path)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,156 @@
+// Package bundle serializes a gathered and validated template set into a single versioned file, and serves it
+// back as gatherers, so services can start without walking the filesystem for templates.
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/BlindGarret/echorend"
+)
+
+// Version is the bundle format this package writes and reads. Bundles of any other version are rejected,
+// and must be regenerated.
+const Version = 1
+
+// Roles of the templates in a bundle.
+const (
+	RoleView    = "view"
+	RolePartial = "partial"
+	RoleLayout  = "layout"
+)
+
+// ErrUnsupportedVersion is returned when reading a bundle written in another format version.
+var ErrUnsupportedVersion = errors.New("unsupported bundle version")
+
+// Bundle is a gathered and validated template set, serialized so it can be loaded without walking the filesystem.
+type Bundle struct {
+	Version int `json:"version"`
+	// DefaultLayout is the layout views are rendered in unless a render picks another one.
+	DefaultLayout string     `json:"defaultLayout,omitempty"`
+	Templates     []Template `json:"templates"`
+}
+
+// Template is a single template of a bundle, with the names of the partials it references.
+type Template struct {
+	Role     string   `json:"role"`
+	Name     string   `json:"name"`
+	Source   string   `json:"source,omitempty"`
+	Gatherer string   `json:"gatherer,omitempty"`
+	Hash     string   `json:"hash"`
+	Data     string   `json:"data"`
+	Partials []string `json:"partials,omitempty"`
+}
+
+// Write encodes the bundle, stamping it with the current Version.
+func Write(w io.Writer, b *Bundle) error {
+	b.Version = Version
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Read decodes a bundle and validates it, see Validate.
+func Read(r io.Reader) (*Bundle, error) {
+	b := &Bundle{}
+	if err := json.NewDecoder(r).Decode(b); err != nil {
+		return nil, fmt.Errorf("decoding bundle: %w", err)
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Load reads and validates the bundle file at path.
+func Load(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// LoadFS reads and validates the bundle file at name in fsys, such as an embed.FS.
+func LoadFS(fsys fs.FS, name string) (*Bundle, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Validate checks the bundle's version, that every template's data matches its hash, and that every partial
+// and the default layout it refers to is in the bundle. Every problem found is returned together as an
+// *echorend.SetupError, template problems as *echorend.TemplateError.
+func (b *Bundle) Validate() error {
+	if b.Version != Version {
+		return fmt.Errorf("%w %d, want %d", ErrUnsupportedVersion, b.Version, Version)
+	}
+
+	names := make(map[string]map[string]bool)
+	for _, template := range b.Templates {
+		if names[template.Role] == nil {
+			names[template.Role] = make(map[string]bool)
+		}
+		names[template.Role][template.Name] = true
+	}
+
+	errs := make([]error, 0)
+	for _, template := range b.Templates {
+		switch template.Role {
+		case RoleView, RolePartial, RoleLayout:
+		default:
+			errs = append(errs, templateError(template, fmt.Errorf("unknown role %q", template.Role)))
+			continue
+		}
+		if echorend.ContentHash(template.Data) != template.Hash {
+			errs = append(errs, templateError(template, errors.New("data does not match its hash")))
+		}
+		for _, partial := range template.Partials {
+			if !names[RolePartial][partial] {
+				errs = append(errs, templateError(template, fmt.Errorf("partial %s not found", partial)))
+			}
+		}
+	}
+	if b.DefaultLayout != "" && !names[RoleLayout][b.DefaultLayout] {
+		errs = append(errs, fmt.Errorf("default layout %s not found", b.DefaultLayout))
+	}
+
+	if len(errs) > 0 {
+		return &echorend.SetupError{Errors: errs}
+	}
+	return nil
+}
+
+// Gatherer returns a gatherer of the bundle's templates with the given role.
+func (b *Bundle) Gatherer(role string) *BundleGatherer {
+	templates := make([]echorend.RawTemplateData, 0)
+	for _, template := range b.Templates {
+		if template.Role != role {
+			continue
+		}
+		templates = append(templates, echorend.RawTemplateData{
+			TemplateName: template.Name,
+			TemplateData: template.Data,
+			Source:       template.Source,
+			Hash:         template.Hash,
+			Gatherer:     template.Gatherer,
+		})
+	}
+	return &BundleGatherer{templates: templates}
+}
+
+func templateError(template Template, err error) error {
+	return &echorend.TemplateError{
+		TemplateName: template.Name,
+		Source:       template.Source,
+		Err:          err,
+	}
+}
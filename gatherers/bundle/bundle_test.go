@@ -0,0 +1,164 @@
+package bundle_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/bundle"
+)
+
+func template(role, name, data string, partials ...string) bundle.Template {
+	return bundle.Template{
+		Role:     role,
+		Name:     name,
+		Source:   name + ".hbs",
+		Hash:     echorend.ContentHash(data),
+		Data:     data,
+		Partials: partials,
+	}
+}
+
+func validBundle() *bundle.Bundle {
+	return &bundle.Bundle{
+		DefaultLayout: "main",
+		Templates: []bundle.Template{
+			template(bundle.RoleView, "index", "{{> item}}", "item"),
+			template(bundle.RolePartial, "item", "item"),
+			template(bundle.RoleLayout, "main", "{{{@body}}}"),
+		},
+	}
+}
+
+func TestBundle_Gatherer_CompliesWithRawTemplateGatherer(t *testing.T) {
+	gatherer := validBundle().Gatherer(bundle.RoleView)
+	_, ok := interface{}(gatherer).(echorend.RawTemplateGatherer)
+	if !ok {
+		t.Fatalf("BundleGatherer does not comply with RawTemplateGatherer interface")
+	}
+}
+
+func TestBundle_WriteThenRead_RoundTrips(t *testing.T) {
+	buf := new(bytes.Buffer)
+	original := validBundle()
+
+	if err := bundle.Write(buf, original); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	read, err := bundle.Read(buf)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if read.Version != bundle.Version {
+		t.Errorf("expected version %d, got %d", bundle.Version, read.Version)
+	}
+	if !reflect.DeepEqual(read, original) {
+		t.Errorf("expected %+v, got %+v", original, read)
+	}
+}
+
+func TestBundle_ReadOtherVersion_ReturnsUnsupportedVersion(t *testing.T) {
+	_, err := bundle.Read(strings.NewReader(`{"version": 99, "templates": []}`))
+
+	if !errors.Is(err, bundle.ErrUnsupportedVersion) {
+		t.Fatalf("expected %v, got %v", bundle.ErrUnsupportedVersion, err)
+	}
+}
+
+func TestBundle_ReadInvalidJSON_ReturnsError(t *testing.T) {
+	_, err := bundle.Read(strings.NewReader(`{`))
+
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestBundle_ValidateTamperedData_ReturnsTemplateError(t *testing.T) {
+	b := validBundle()
+	b.Version = bundle.Version
+	b.Templates[1].Data = "changed"
+
+	err := b.Validate()
+
+	var templateErr *echorend.TemplateError
+	if !errors.As(err, &templateErr) {
+		t.Fatalf("expected TemplateError, got %v", err)
+	}
+	if templateErr.TemplateName != "item" || templateErr.Source != "item.hbs" {
+		t.Errorf("expected the error located at item.hbs, got %v", templateErr)
+	}
+}
+
+func TestBundle_ValidateBrokenRelationships_ReportsEveryProblem(t *testing.T) {
+	b := validBundle()
+	b.Version = bundle.Version
+	b.DefaultLayout = "missing-layout"
+	b.Templates[0].Partials = []string{"item", "missing-partial"}
+	b.Templates = append(b.Templates, template("fragment", "odd", "odd"))
+
+	err := b.Validate()
+
+	var setupErr *echorend.SetupError
+	if !errors.As(err, &setupErr) {
+		t.Fatalf("expected SetupError, got %v", err)
+	}
+	if len(setupErr.Errors) != 3 {
+		t.Errorf("expected 3 errors, got %v", setupErr.Errors)
+	}
+	for _, expected := range []string{"missing-partial", "missing-layout", "unknown role"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q reported, got %v", expected, err)
+		}
+	}
+}
+
+func TestBundle_LoadFS_ReadsBundle(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := bundle.Write(buf, validBundle()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, err := bundle.LoadFS(fstest.MapFS{"templates.bundle.json": {Data: buf.Bytes()}}, "templates.bundle.json")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(b.Templates) != 3 {
+		t.Errorf("expected 3 templates, got %d", len(b.Templates))
+	}
+}
+
+func TestBundle_Load_MissingFileReturnsError(t *testing.T) {
+	_, err := bundle.Load("testdata/missing.json")
+
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestBundleGatherer_Gather_ReturnsTemplatesOfRole(t *testing.T) {
+	gatherer := validBundle().Gatherer(bundle.RolePartial)
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []echorend.RawTemplateData{{
+		TemplateName: "item",
+		TemplateData: "item",
+		Source:       "item.hbs",
+		Hash:         echorend.ContentHash("item"),
+	}}
+	if !reflect.DeepEqual(templates, expected) {
+		t.Errorf("expected %v, got %v", expected, templates)
+	}
+	if !reflect.DeepEqual(gatherer.MustGather(), expected) {
+		t.Errorf("expected MustGather to match Gather")
+	}
+}
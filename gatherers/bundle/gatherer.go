@@ -0,0 +1,21 @@
+package bundle
+
+import "github.com/BlindGarret/echorend"
+
+// BundleGatherer serves templates already loaded from a bundle, so gathering never touches the filesystem.
+type BundleGatherer struct {
+	templates []echorend.RawTemplateData
+}
+
+// MustGather returns the bundled templates. It never panics, as the bundle was validated when it was loaded.
+func (g *BundleGatherer) MustGather() []echorend.RawTemplateData {
+	templates, _ := g.Gather()
+	return templates
+}
+
+// Gather returns copies of the bundled templates.
+func (g *BundleGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	templates := make([]echorend.RawTemplateData, len(g.templates))
+	copy(templates, g.templates)
+	return templates, nil
+}
@@ -0,0 +1,175 @@
+package composite
+
+import (
+	"fmt"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/namespace"
+)
+
+// Layer is one gatherer of a CompositeGatherer, named for reporting where templates came from.
+type Layer struct {
+	Name     string
+	Gatherer echorend.RawTemplateGatherer
+	// Namespace qualifies the names of the layer's templates, such as billing:invoice/show. Layers in different
+	// namespaces never override each other.
+	Namespace string
+}
+
+// CompositeGathererConfig is a configuration struct for creating a CompositeGatherer.
+type CompositeGathererConfig struct {
+	// Layers are gathered in order. Layers without a name are named by their position, such as "layer 0".
+	Layers []Layer
+	// CollisionPolicy decides which layer's template is used when several layers have a template with the same name.
+	// CollisionLastWins lets later layers override earlier ones, such as an application's templates over a base theme.
+	// The default, CollisionFail, reports every name more than one layer has.
+	CollisionPolicy echorend.CollisionPolicy
+}
+
+// ResolvedTemplate is a template chosen by a CompositeGatherer, along with the layer it came from and the layers
+// whose templates of the same name it overrode.
+type ResolvedTemplate struct {
+	Template   echorend.RawTemplateData
+	Layer      string
+	Overridden []string
+}
+
+// CompositeGatherer merges the templates of several gatherers by name, letting one layer override another.
+type CompositeGatherer struct {
+	config CompositeGathererConfig
+}
+
+// NewCompositeGatherer creates a composite of the gatherers in order, where later gatherers override earlier ones.
+func NewCompositeGatherer(gatherers ...echorend.RawTemplateGatherer) *CompositeGatherer {
+	layers := make([]Layer, 0, len(gatherers))
+	for _, gatherer := range gatherers {
+		layers = append(layers, Layer{Gatherer: gatherer})
+	}
+	return NewCompositeGathererWithConfig(CompositeGathererConfig{
+		Layers:          layers,
+		CollisionPolicy: echorend.CollisionLastWins,
+	})
+}
+
+func NewCompositeGathererWithConfig(config CompositeGathererConfig) *CompositeGatherer {
+	return &CompositeGatherer{
+		config: defaultCompositeGathererConfig(config),
+	}
+}
+
+// MustGather attempts to gather templates from every layer. If an error occurs, it panics.
+func (g *CompositeGatherer) MustGather() []echorend.RawTemplateData {
+	templates, err := g.Gather()
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// Gather gets the templates of every layer, merged by name.
+func (g *CompositeGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	resolved, err := g.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]echorend.RawTemplateData, 0, len(resolved))
+	for _, template := range resolved {
+		templates = append(templates, template.Template)
+	}
+	return templates, nil
+}
+
+// Resolve gets the templates of every layer, merged by name, reporting the layer each template came from.
+// Templates are returned in the order their names were first gathered. A layer with several templates of the same
+// name is always a collision, as the policy only decides between layers.
+func (g *CompositeGatherer) Resolve() ([]ResolvedTemplate, error) {
+	resolved := make([]ResolvedTemplate, 0)
+	indexes := make(map[string]int)
+	collisions := make([]echorend.Collision, 0)
+	collisionIndexes := make(map[string]int)
+	collide := func(name, first, source string) {
+		c, ok := collisionIndexes[name]
+		if !ok {
+			c = len(collisions)
+			collisionIndexes[name] = c
+			collisions = append(collisions, echorend.Collision{TemplateName: name, Sources: []string{first}})
+		}
+		collisions[c].Sources = append(collisions[c].Sources, source)
+	}
+
+	for _, layer := range g.config.Layers {
+		templates, err := layer.Gatherer.Gather()
+		if err != nil {
+			return nil, fmt.Errorf("gathering layer %s: %w", layer.Name, err)
+		}
+		sources := make(map[string]string)
+		for _, template := range namespace.Apply(layer.Namespace, templates) {
+			source := layerSource(layer.Name, template)
+			if first, ok := sources[template.TemplateName]; ok {
+				collide(template.TemplateName, first, source)
+				continue
+			}
+			sources[template.TemplateName] = source
+
+			i, ok := indexes[template.TemplateName]
+			if !ok {
+				indexes[template.TemplateName] = len(resolved)
+				resolved = append(resolved, ResolvedTemplate{
+					Template:   template,
+					Layer:      layer.Name,
+					Overridden: make([]string, 0),
+				})
+				continue
+			}
+			existing := resolved[i]
+			switch g.config.CollisionPolicy {
+			case echorend.CollisionFirstWins:
+				resolved[i].Overridden = append(existing.Overridden, layer.Name)
+			case echorend.CollisionLastWins:
+				resolved[i] = ResolvedTemplate{
+					Template:   template,
+					Layer:      layer.Name,
+					Overridden: append(existing.Overridden, existing.Layer),
+				}
+			default:
+				collide(template.TemplateName, layerSource(existing.Layer, existing.Template), source)
+			}
+		}
+	}
+
+	if len(collisions) > 0 {
+		return nil, &echorend.CollisionError{Collisions: collisions}
+	}
+	return resolved, nil
+}
+
+// layerSource describes where a template came from for collision errors, such as theme: templates/index.hbs.
+func layerSource(layer string, template echorend.RawTemplateData) string {
+	if template.Source == "" {
+		return layer
+	}
+	return layer + ": " + template.Source
+}
+
+// WatchDirs returns the directories of every watchable layer, so a renderer can hot reload a composite.
+func (g *CompositeGatherer) WatchDirs() []string {
+	dirs := make([]string, 0)
+	for _, layer := range g.config.Layers {
+		if watchable, ok := layer.Gatherer.(echorend.WatchableGatherer); ok {
+			dirs = append(dirs, watchable.WatchDirs()...)
+		}
+	}
+	return dirs
+}
+
+func defaultCompositeGathererConfig(config CompositeGathererConfig) CompositeGathererConfig {
+	layers := make([]Layer, 0, len(config.Layers))
+	for i, layer := range config.Layers {
+		if layer.Name == "" {
+			layer.Name = fmt.Sprintf("layer %d", i)
+		}
+		layers = append(layers, layer)
+	}
+	config.Layers = layers
+	return config
+}
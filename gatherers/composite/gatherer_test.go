@@ -0,0 +1,209 @@
+package composite_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/composite"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+)
+
+func template(name, data string) echorend.RawTemplateData {
+	return echorend.RawTemplateData{TemplateName: name, TemplateData: data}
+}
+
+func newLayers() []composite.Layer {
+	return []composite.Layer{
+		{Name: "theme", Gatherer: NewMockTemplateGatherer(template("index", "theme index"), template("about", "theme about"))},
+		{Name: "site", Gatherer: NewMockTemplateGatherer(template("index", "site index"), template("contact", "site contact"))},
+	}
+}
+
+func TestCompositeGatherer_Interface_CompliesWithWatchableGatherer(t *testing.T) {
+	gatherer := composite.NewCompositeGatherer()
+	_, ok := interface{}(gatherer).(echorend.WatchableGatherer)
+	if !ok {
+		t.Fatalf("CompositeGatherer does not comply with WatchableGatherer interface")
+	}
+}
+
+func TestCompositeGathererGather_LastWins_LaterLayerOverrides(t *testing.T) {
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers:          newLayers(),
+		CollisionPolicy: echorend.CollisionLastWins,
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []echorend.RawTemplateData{
+		template("index", "site index"),
+		template("about", "theme about"),
+		template("contact", "site contact"),
+	}
+	if !reflect.DeepEqual(templates, expected) {
+		t.Errorf("Expected %v, got %v", expected, templates)
+	}
+}
+
+func TestCompositeGathererGather_FirstWins_EarlierLayerKept(t *testing.T) {
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers:          newLayers(),
+		CollisionPolicy: echorend.CollisionFirstWins,
+	})
+
+	templates := gatherer.MustGather()
+
+	if templates[0].TemplateData != "theme index" || len(templates) != 3 {
+		t.Errorf("Expected the theme index to be kept, got %v", templates)
+	}
+}
+
+func TestCompositeGathererGather_DefaultPolicy_ReturnsCollisionError(t *testing.T) {
+	theme := NewMockTemplateGatherer(echorend.RawTemplateData{TemplateName: "index", Source: "theme/index.hbs"})
+	site := NewMockTemplateGatherer(echorend.RawTemplateData{TemplateName: "index", Source: "site/index.hbs"})
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers: []composite.Layer{{Name: "theme", Gatherer: theme}, {Name: "site", Gatherer: site}},
+	})
+
+	_, err := gatherer.Gather()
+
+	var collisionErr *echorend.CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("Expected a CollisionError, got %v", err)
+	}
+	expected := []echorend.Collision{{TemplateName: "index", Sources: []string{"theme: theme/index.hbs", "site: site/index.hbs"}}}
+	if !reflect.DeepEqual(collisionErr.Collisions, expected) {
+		t.Errorf("Expected %v, got %v", expected, collisionErr.Collisions)
+	}
+}
+
+func TestCompositeGathererGather_DuplicateInOneLayer_ReturnsCollisionError(t *testing.T) {
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers: []composite.Layer{
+			{Name: "theme", Gatherer: NewMockTemplateGatherer(template("index", "theme index"))},
+			{Name: "site", Gatherer: NewMockTemplateGatherer(template("index", "site index"), template("index", "other index"))},
+		},
+		CollisionPolicy: echorend.CollisionLastWins,
+	})
+
+	_, err := gatherer.Gather()
+
+	var collisionErr *echorend.CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("Expected a CollisionError, got %v", err)
+	}
+	expected := []echorend.Collision{{TemplateName: "index", Sources: []string{"site", "site"}}}
+	if !reflect.DeepEqual(collisionErr.Collisions, expected) {
+		t.Errorf("Expected %v, got %v", expected, collisionErr.Collisions)
+	}
+}
+
+func TestCompositeGathererResolve_Overrides_ReportsLayers(t *testing.T) {
+	layers := append(newLayers(), composite.Layer{Gatherer: NewMockTemplateGatherer(template("index", "local index"))})
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers:          layers,
+		CollisionPolicy: echorend.CollisionLastWins,
+	})
+
+	resolved, err := gatherer.Resolve()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resolved[0].Layer != "layer 2" || !reflect.DeepEqual(resolved[0].Overridden, []string{"theme", "site"}) {
+		t.Errorf("Unexpected index resolution %+v", resolved[0])
+	}
+	if resolved[1].Layer != "theme" || len(resolved[1].Overridden) != 0 {
+		t.Errorf("Unexpected about resolution %+v", resolved[1])
+	}
+}
+
+func TestCompositeGathererResolve_FirstWins_ReportsShadowedLayers(t *testing.T) {
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers:          newLayers(),
+		CollisionPolicy: echorend.CollisionFirstWins,
+	})
+
+	resolved, _ := gatherer.Resolve()
+
+	if resolved[0].Layer != "theme" || !reflect.DeepEqual(resolved[0].Overridden, []string{"site"}) {
+		t.Errorf("Unexpected index resolution %+v", resolved[0])
+	}
+}
+
+func TestCompositeGathererGather_LayerErrors_ReturnsErrorNamingLayer(t *testing.T) {
+	broken := NewMockTemplateGatherer()
+	broken.err = errors.New("disk gone")
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers: []composite.Layer{{Name: "overrides", Gatherer: broken}},
+	})
+
+	_, err := gatherer.Gather()
+
+	if err == nil || !strings.Contains(err.Error(), "overrides") {
+		t.Errorf("Expected an error naming the layer, got %v", err)
+	}
+}
+
+func TestCompositeGathererWatchDirs_WatchableLayers_ReturnsTheirDirs(t *testing.T) {
+	gatherer := composite.NewCompositeGatherer(
+		&MockWatchableGatherer{MockTemplateGatherer: NewMockTemplateGatherer(), dirs: []string{"theme"}},
+		NewMockTemplateGatherer(),
+		&MockWatchableGatherer{MockTemplateGatherer: NewMockTemplateGatherer(), dirs: []string{"site"}},
+	)
+
+	dirs := gatherer.WatchDirs()
+
+	if !reflect.DeepEqual(dirs, []string{"theme", "site"}) {
+		t.Errorf("Expected theme and site, got %v", dirs)
+	}
+}
+
+func TestCompositeGatherer_AsHandlebarsGatherers_RendersOverrides(t *testing.T) {
+	views := composite.NewCompositeGatherer(
+		NewMockTemplateGatherer(template("page", "{{> header}} theme page")),
+		NewMockTemplateGatherer(template("other", "site other")),
+	)
+	partials := composite.NewCompositeGatherer(
+		NewMockTemplateGatherer(template("header", "theme header")),
+		NewMockTemplateGatherer(template("header", "site header")),
+	)
+	renderer := handlebars.NewHandlebarsRenderer(views, partials)
+	renderer.MustSetup()
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "page", nil, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "site header theme page" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestCompositeGathererGather_NamespacedLayers_DoNotOverride(t *testing.T) {
+	gatherer := composite.NewCompositeGathererWithConfig(composite.CompositeGathererConfig{
+		Layers: []composite.Layer{
+			{Gatherer: NewMockTemplateGatherer(template("index", "app index"))},
+			{Gatherer: NewMockTemplateGatherer(template("index", "billing index")), Namespace: "billing"},
+		},
+	})
+
+	templates := gatherer.MustGather()
+
+	expected := []echorend.RawTemplateData{
+		template("index", "app index"),
+		template("billing:index", "billing index"),
+	}
+	if !reflect.DeepEqual(templates, expected) {
+		t.Errorf("Expected %v, got %v", expected, templates)
+	}
+}
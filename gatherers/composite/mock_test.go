@@ -0,0 +1,34 @@
+package composite_test
+
+import "github.com/BlindGarret/echorend"
+
+type MockTemplateGatherer struct {
+	templates []echorend.RawTemplateData
+	err       error
+}
+
+func NewMockTemplateGatherer(templates ...echorend.RawTemplateData) *MockTemplateGatherer {
+	return &MockTemplateGatherer{
+		templates: templates,
+	}
+}
+
+func (m *MockTemplateGatherer) MustGather() []echorend.RawTemplateData {
+	if m.err != nil {
+		panic(m.err)
+	}
+	return m.templates
+}
+
+func (m *MockTemplateGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	return m.templates, m.err
+}
+
+type MockWatchableGatherer struct {
+	*MockTemplateGatherer
+	dirs []string
+}
+
+func (m *MockWatchableGatherer) WatchDirs() []string {
+	return m.dirs
+}
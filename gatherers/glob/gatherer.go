@@ -1,11 +1,16 @@
 package glob
 
 import (
+	"errors"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/BlindGarret/echorend"
 	"github.com/BlindGarret/echorend/externals"
+	"github.com/BlindGarret/echorend/gatherers/internal/extensions"
 )
 
 // GlobGathererConfig is a configuration struct for creating a GlobGatherer.
@@ -14,6 +19,19 @@ type GlobGathererConfig struct {
 	FileAccess      externals.FileAccess
 	IncludeTLDInKey bool
 	Extensions      []string
+	// MaxDepth limits how many directories deep templates are found, 1 being only the template directory itself.
+	// Zero means no limit.
+	MaxDepth int
+	// Include and Exclude filter templates by glob patterns, matched against the path relative to the template directory.
+	// Patterns without a slash also match against the file name alone, as in `*.test.hbs`. Patterns ending in a slash
+	// or /** match directories, and so every template below them, as in `_drafts/` at any depth or `admin/**`, while
+	// `admin/*` only matches the templates directly in admin. When Include is empty every template is included.
+	Include []string
+	Exclude []string
+	// CollisionPolicy decides what happens when templates resolve to the same name, such as index.hbs and index.handlebars.
+	// Colliding templates are ordered by the position of their extension in Extensions, then by path, so with
+	// CollisionFirstWins the extension listed first wins.
+	CollisionPolicy echorend.CollisionPolicy
 }
 
 // GlobGatherer is a gatherer for getting templates from the filesystem using glob patterns.
@@ -36,34 +54,42 @@ func (g *GlobGatherer) MustGather() []echorend.RawTemplateData {
 	return templates
 }
 
-// Gather gets templates from the filesystem using glob patterns.
+// Gather gets templates from the filesystem, at any depth below the template directory.
 func (g *GlobGatherer) Gather() ([]echorend.RawTemplateData, error) {
 	templates := make([]echorend.RawTemplateData, 0)
 
-	for _, extension := range g.config.Extensions {
-		files, err := getTemplateFiles(extension, *g.config.TemplateDir, g.config.FileAccess)
+	files, err := g.getTemplateFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		templateName := getTemplateName(file.path, *g.config.TemplateDir)
+		if g.config.IncludeTLDInKey {
+			templateName = *g.config.TemplateDir + "/" + templateName
+		}
+		bs, err := g.config.FileAccess.ReadFile(file.path)
 		if err != nil {
 			return nil, err
 		}
-
-		for _, file := range files {
-			templateName := getTemplateName(file, *g.config.TemplateDir)
-			if g.config.IncludeTLDInKey {
-				templateName = *g.config.TemplateDir + "/" + templateName
-			}
-			bs, err := g.config.FileAccess.ReadFile(file)
-			if err != nil {
-				return nil, err
-			}
-			data := echorend.RawTemplateData{
-				TemplateName: templateName,
-				TemplateData: string(bs),
-			}
-			templates = append(templates, data)
+		data := echorend.RawTemplateData{
+			TemplateName: templateName,
+			TemplateData: string(bs),
+			Source:       file.path,
+			ModTime:      file.modTime,
+			Hash:         echorend.ContentHash(string(bs)),
+			Gatherer:     "glob:" + *g.config.TemplateDir,
 		}
+		templates = append(templates, data)
 	}
 
-	return templates, nil
+	extensions.Sort(templates, g.config.Extensions)
+	return echorend.ResolveCollisions(templates, g.config.CollisionPolicy)
+}
+
+// WatchDirs returns the directory the gatherer reads templates from, so renderers can watch it for changes.
+func (g *GlobGatherer) WatchDirs() []string {
+	return []string{*g.config.TemplateDir}
 }
 
 func defaultGlobGathererConfig(config GlobGathererConfig) GlobGathererConfig {
@@ -79,25 +105,110 @@ func defaultGlobGathererConfig(config GlobGathererConfig) GlobGathererConfig {
 	return config
 }
 
-func getTemplateFiles(extension string, topLevelDirectory string, fileAccess externals.FileAccess) ([]string, error) {
-	files, err := fileAccess.Glob(topLevelDirectory + "/*" + extension)
-	if err != nil {
-		return nil, err
-	}
+type templateFile struct {
+	path    string
+	modTime time.Time
+}
+
+func (g *GlobGatherer) getTemplateFiles() ([]templateFile, error) {
+	tld := *g.config.TemplateDir
+	files := make([]templateFile, 0)
+
+	err := walkDir(g.config.FileAccess, tld, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// a template directory which doesn't exist yet has no templates, as when globbing it
+			if file == tld && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		rel := relativePath(file, tld)
+		if d.IsDir() {
+			if rel == "." {
+				return nil
+			}
+			if matchesAny(rel, true, g.config.Exclude) ||
+				(g.config.MaxDepth > 0 && depth(rel) >= g.config.MaxDepth) {
+				return fs.SkipDir
+			}
+			return nil
+		}
 
-	recursiveMatches, err := fileAccess.Glob(topLevelDirectory + "/**/*" + extension)
+		if !extensions.Has(file, g.config.Extensions) || matchesAny(rel, false, g.config.Exclude) {
+			return nil
+		}
+		if len(g.config.Include) > 0 && !matchesAny(rel, false, g.config.Include) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, templateFile{path: file, modTime: info.ModTime()})
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	files = append(files, recursiveMatches...)
 	return files, nil
 }
 
 func getTemplateName(path string, tld string) string {
-	path = strings.Replace(path, tld, "", 1)
-	if strings.HasPrefix(path, "/") {
-		// Remove leading slash
-		path = path[1:]
-	}
+	path = relativePath(path, tld)
 	return path[:len(path)-len(filepath.Ext(path))]
 }
+
+// relativePath returns path relative to the template directory, always slash separated.
+func relativePath(path string, tld string) string {
+	rel, err := filepath.Rel(tld, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func depth(rel string) int {
+	return strings.Count(rel, "/") + 1
+}
+
+func matchesAny(rel string, isDir bool, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matches(rel, isDir, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(rel string, isDir bool, pattern string) bool {
+	if strings.HasSuffix(pattern, "/**") {
+		pattern = strings.TrimSuffix(pattern, "**")
+	}
+	if !strings.HasSuffix(pattern, "/") {
+		return matchesPath(rel, pattern)
+	}
+
+	// directory patterns match a file through any of the directories it is in
+	pattern = strings.TrimSuffix(pattern, "/")
+	dir := rel
+	if !isDir {
+		dir = path.Dir(rel)
+	}
+	for ; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if matchesPath(dir, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPath(rel string, pattern string) bool {
+	if ok, _ := path.Match(pattern, rel); ok {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return false
+}
@@ -2,7 +2,10 @@ package glob_test
 
 import (
 	"errors"
+	"io/fs"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/BlindGarret/echorend"
 	"github.com/BlindGarret/echorend/gatherers/glob"
@@ -28,24 +31,14 @@ func TestGlobGatherer_HappyPathNoTLD_ReturnsAsExpected(t *testing.T) {
 		filePath     string
 		expectedName string
 		expectedData string
-		flat         bool
 	}{
-		{"templates/file1.html", "file1", "file1", true},
-		{"templates/file2.html", "file2", "file2", true},
-		{"templates/nested/file3.html", "nested/file3", "file3", false},
+		{"templates/file1.html", "file1", "file1"},
+		{"templates/file2.html", "file2", "file2"},
+		{"templates/nested/file3.html", "nested/file3", "file3"},
 	}
-	flatFilePaths := make([]string, 0)
-	nestedFilePaths := make([]string, 0)
 	for _, f := range files {
-		if f.flat {
-			flatFilePaths = append(flatFilePaths, f.filePath)
-		} else {
-			nestedFilePaths = append(nestedFilePaths, f.filePath)
-		}
 		mockFileAccess.RegisterFile(f.filePath, []byte(f.expectedData), nil)
 	}
-	mockFileAccess.RegisterGlob("templates/*.html", flatFilePaths, nil)
-	mockFileAccess.RegisterGlob("templates/**/*.html", nestedFilePaths, nil)
 
 	fs, err := gatherer.Gather()
 
@@ -78,24 +71,14 @@ func TestGlobGatherer_HappyPathWithTLD_ReturnsAsExpected(t *testing.T) {
 		filePath     string
 		expectedName string
 		expectedData string
-		flat         bool
 	}{
-		{"templates/file1.html", "templates/file1", "file1", true},
-		{"templates/file2.html", "templates/file2", "file2", true},
-		{"templates/nested/file3.html", "templates/nested/file3", "file3", false},
+		{"templates/file1.html", "templates/file1", "file1"},
+		{"templates/file2.html", "templates/file2", "file2"},
+		{"templates/nested/file3.html", "templates/nested/file3", "file3"},
 	}
-	flatFilePaths := make([]string, 0)
-	nestedFilePaths := make([]string, 0)
 	for _, f := range files {
-		if f.flat {
-			flatFilePaths = append(flatFilePaths, f.filePath)
-		} else {
-			nestedFilePaths = append(nestedFilePaths, f.filePath)
-		}
 		mockFileAccess.RegisterFile(f.filePath, []byte(f.expectedData), nil)
 	}
-	mockFileAccess.RegisterGlob("templates/*.html", flatFilePaths, nil)
-	mockFileAccess.RegisterGlob("templates/**/*.html", nestedFilePaths, nil)
 
 	fs, err := gatherer.Gather()
 
@@ -115,7 +98,7 @@ func TestGlobGatherer_HappyPathWithTLD_ReturnsAsExpected(t *testing.T) {
 	}
 }
 
-func TestGlobGatherer_ErrorGlobbingRecursive_ReturnsError(t *testing.T) {
+func TestGlobGatherer_ErrorWalkingNestedDir_ReturnsError(t *testing.T) {
 	templateDir := "templates"
 	mockFileAccess := NewMemoryFileAccess()
 	expectedErr := errors.New("test error")
@@ -124,8 +107,8 @@ func TestGlobGatherer_ErrorGlobbingRecursive_ReturnsError(t *testing.T) {
 		FileAccess:  mockFileAccess,
 		Extensions:  []string{".html"},
 	})
-	mockFileAccess.RegisterGlob("templates/*.html", []string{}, nil)
-	mockFileAccess.RegisterGlob("templates/**/*.html", nil, expectedErr)
+	mockFileAccess.RegisterFile("templates/nested/file1.html", []byte("file1"), nil)
+	mockFileAccess.RegisterWalkError("templates/nested", expectedErr)
 
 	_, err := gatherer.Gather()
 
@@ -134,7 +117,7 @@ func TestGlobGatherer_ErrorGlobbingRecursive_ReturnsError(t *testing.T) {
 	}
 }
 
-func TestGlobGatherer_ErrorGlobbing_ReturnsError(t *testing.T) {
+func TestGlobGatherer_ErrorWalking_ReturnsError(t *testing.T) {
 	templateDir := "templates"
 	mockFileAccess := NewMemoryFileAccess()
 	expectedErr := errors.New("test error")
@@ -143,7 +126,7 @@ func TestGlobGatherer_ErrorGlobbing_ReturnsError(t *testing.T) {
 		FileAccess:  mockFileAccess,
 		Extensions:  []string{".html"},
 	})
-	mockFileAccess.RegisterGlob("templates/*.html", nil, expectedErr)
+	mockFileAccess.RegisterWalkError("templates", expectedErr)
 
 	_, err := gatherer.Gather()
 
@@ -152,6 +135,85 @@ func TestGlobGatherer_ErrorGlobbing_ReturnsError(t *testing.T) {
 	}
 }
 
+func TestGlobGatherer_MissingTemplateDir_ReturnsNoTemplates(t *testing.T) {
+	templateDir := "templates"
+	mockFileAccess := NewMemoryFileAccess()
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+		FileAccess:  mockFileAccess,
+		Extensions:  []string{".html"},
+	})
+	mockFileAccess.RegisterWalkError("templates", fs.ErrNotExist)
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 0 {
+		t.Fatalf("expected no templates, got %v", templates)
+	}
+}
+
+func TestGlobGatherer_MissingTemplateDirOnDisk_ReturnsNoTemplates(t *testing.T) {
+	templateDir := filepath.Join(t.TempDir(), "missing")
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+		Extensions:  []string{".html"},
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 0 {
+		t.Fatalf("expected no templates, got %v", templates)
+	}
+}
+
+func TestGlobGatherer_MissingNestedDir_ReturnsError(t *testing.T) {
+	templateDir := "templates"
+	mockFileAccess := NewMemoryFileAccess()
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+		FileAccess:  mockFileAccess,
+		Extensions:  []string{".html"},
+	})
+	mockFileAccess.RegisterFile("templates/nested/file1.html", []byte("file1"), nil)
+	mockFileAccess.RegisterWalkError("templates/nested", fs.ErrNotExist)
+
+	_, err := gatherer.Gather()
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
+
+func TestGlobGatherer_FileAccessWithoutWalkDir_WalksWithGlob(t *testing.T) {
+	templateDir := "templates"
+	mockFileAccess := NewMemoryFileAccess()
+	mockFileAccess.RegisterFile("templates/index.hbs", []byte("index"), nil)
+	mockFileAccess.RegisterFile("templates/admin/users/edit.hbs", []byte("edit"), nil)
+	mockFileAccess.RegisterFile("templates/admin/notes.txt", []byte("notes"), nil)
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+		FileAccess:  GlobFileAccess{memory: mockFileAccess},
+		Extensions:  []string{".hbs"},
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := make([]string, 0)
+	for _, template := range templates {
+		names = append(names, template.TemplateName)
+	}
+	assertNames(t, []string{"admin/users/edit", "index"}, names)
+}
+
 func TestGlobGatherer_ErrorReadingFile_ReturnsError(t *testing.T) {
 	templateDir := "templates"
 	mockFileAccess := NewMemoryFileAccess()
@@ -161,7 +223,6 @@ func TestGlobGatherer_ErrorReadingFile_ReturnsError(t *testing.T) {
 		FileAccess:  mockFileAccess,
 		Extensions:  []string{".html"},
 	})
-	mockFileAccess.RegisterGlob("templates/*.html", []string{"templates/file1.html"}, nil)
 	mockFileAccess.RegisterFile("templates/file1.html", nil, expectedErr)
 
 	_, err := gatherer.Gather()
@@ -183,24 +244,14 @@ func TestGlobGatherer_HappyPathWithRelativePaths_ReturnsAsExpected(t *testing.T)
 		filePath     string
 		expectedName string
 		expectedData string
-		flat         bool
 	}{
-		{"./templates/file1.html", "file1", "file1", true},
-		{"./templates/file2.html", "file2", "file2", true},
-		{"./templates/nested/file3.html", "nested/file3", "file3", false},
+		{"./templates/file1.html", "file1", "file1"},
+		{"./templates/file2.html", "file2", "file2"},
+		{"./templates/nested/file3.html", "nested/file3", "file3"},
 	}
-	flatFilePaths := make([]string, 0)
-	nestedFilePaths := make([]string, 0)
 	for _, f := range files {
-		if f.flat {
-			flatFilePaths = append(flatFilePaths, f.filePath)
-		} else {
-			nestedFilePaths = append(nestedFilePaths, f.filePath)
-		}
 		mockFileAccess.RegisterFile(f.filePath, []byte(f.expectedData), nil)
 	}
-	mockFileAccess.RegisterGlob("./templates/*.html", flatFilePaths, nil)
-	mockFileAccess.RegisterGlob("./templates/**/*.html", nestedFilePaths, nil)
 
 	fs, err := gatherer.Gather()
 
@@ -230,24 +281,14 @@ func TestGlobGatherer_HappyPathMustGatherWithDefaultTLD_ReturnsAsExpected(t *tes
 		filePath     string
 		expectedName string
 		expectedData string
-		flat         bool
 	}{
-		{"templates/views/file1.html", "file1", "file1", true},
-		{"templates/views/file2.html", "file2", "file2", true},
-		{"templates/views/nested/file3.html", "nested/file3", "file3", false},
+		{"templates/views/file1.html", "file1", "file1"},
+		{"templates/views/file2.html", "file2", "file2"},
+		{"templates/views/nested/file3.html", "nested/file3", "file3"},
 	}
-	flatFilePaths := make([]string, 0)
-	nestedFilePaths := make([]string, 0)
 	for _, f := range files {
-		if f.flat {
-			flatFilePaths = append(flatFilePaths, f.filePath)
-		} else {
-			nestedFilePaths = append(nestedFilePaths, f.filePath)
-		}
 		mockFileAccess.RegisterFile(f.filePath, []byte(f.expectedData), nil)
 	}
-	mockFileAccess.RegisterGlob("templates/views/*.html", flatFilePaths, nil)
-	mockFileAccess.RegisterGlob("templates/views/**/*.html", nestedFilePaths, nil)
 
 	fs := gatherer.MustGather()
 
@@ -270,7 +311,7 @@ func TestGlobGatherer_ErrorMustGather_Panics(t *testing.T) {
 		FileAccess: mockFileAccess,
 		Extensions: []string{".html"},
 	})
-	mockFileAccess.RegisterGlob("templates/views/*.html", nil, errors.New("test error"))
+	mockFileAccess.RegisterWalkError("templates/views", errors.New("test error"))
 
 	defer func() {
 		if r := recover(); r == nil {
@@ -280,3 +321,246 @@ func TestGlobGatherer_ErrorMustGather_Panics(t *testing.T) {
 
 	gatherer.MustGather()
 }
+
+func TestGlobGatherer_WatchDirs_ReturnsTemplateDir(t *testing.T) {
+	templateDir := "templates"
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+	})
+
+	dirs := gatherer.WatchDirs()
+
+	if len(dirs) != 1 || dirs[0] != templateDir {
+		t.Errorf("expected [%s], got %v", templateDir, dirs)
+	}
+}
+
+func gatherNames(t *testing.T, config glob.GlobGathererConfig, paths ...string) []string {
+	templateDir := "templates"
+	mockFileAccess := NewMemoryFileAccess()
+	for _, p := range paths {
+		mockFileAccess.RegisterFile(p, []byte(p), nil)
+	}
+	config.TemplateDir = &templateDir
+	config.FileAccess = mockFileAccess
+	config.Extensions = []string{".hbs"}
+
+	templates, err := glob.NewGlobGatherer(config).Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := make([]string, 0)
+	for _, template := range templates {
+		names = append(names, template.TemplateName)
+	}
+	return names
+}
+
+func assertNames(t *testing.T, expected []string, actual []string) {
+	if len(expected) != len(actual) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+}
+
+func TestGlobGatherer_DeeplyNestedTemplates_AreGathered(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{},
+		"templates/index.hbs",
+		"templates/admin/users/edit.hbs",
+		"templates/admin/users/roles/assign.hbs",
+	)
+
+	assertNames(t, []string{"admin/users/edit", "admin/users/roles/assign", "index"}, names)
+}
+
+func TestGlobGatherer_MaxDepth_SkipsDeeperTemplates(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{MaxDepth: 2},
+		"templates/index.hbs",
+		"templates/admin/list.hbs",
+		"templates/admin/users/edit.hbs",
+	)
+
+	assertNames(t, []string{"admin/list", "index"}, names)
+}
+
+func TestGlobGatherer_MaxDepthOne_OnlyGathersTemplateDir(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{MaxDepth: 1},
+		"templates/index.hbs",
+		"templates/admin/list.hbs",
+	)
+
+	assertNames(t, []string{"index"}, names)
+}
+
+func TestGlobGatherer_ExcludeDirectoryPattern_SkipsDirectoryAtAnyDepth(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{Exclude: []string{"_drafts/"}},
+		"templates/_drafts/wip.hbs",
+		"templates/blog/_drafts/post.hbs",
+		"templates/blog/post.hbs",
+	)
+
+	assertNames(t, []string{"blog/post"}, names)
+}
+
+func TestGlobGatherer_ExcludeFilePattern_SkipsMatchingFiles(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{Exclude: []string{"*.test.hbs"}},
+		"templates/index.hbs",
+		"templates/index.test.hbs",
+		"templates/nested/show.test.hbs",
+	)
+
+	assertNames(t, []string{"index"}, names)
+}
+
+func TestGlobGatherer_IncludePattern_OnlyGathersMatchingFiles(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{Include: []string{"admin/*"}},
+		"templates/index.hbs",
+		"templates/admin/list.hbs",
+		"templates/admin/users/edit.hbs",
+	)
+
+	assertNames(t, []string{"admin/list"}, names)
+}
+
+func TestGlobGatherer_IncludeDirectoryPattern_GathersEveryTemplateBelow(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{Include: []string{"admin/"}},
+		"templates/index.hbs",
+		"templates/admin/list.hbs",
+		"templates/admin/users/edit.hbs",
+		"templates/blog/admin.hbs",
+	)
+
+	assertNames(t, []string{"admin/list", "admin/users/edit"}, names)
+}
+
+func TestGlobGatherer_IncludeDoubleStarPattern_GathersEveryTemplateBelow(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{Include: []string{"admin/**"}},
+		"templates/index.hbs",
+		"templates/admin/list.hbs",
+		"templates/admin/users/edit.hbs",
+	)
+
+	assertNames(t, []string{"admin/list", "admin/users/edit"}, names)
+}
+
+func TestGlobGatherer_IncludeNestedDirectoryPattern_GathersOnlyThatDirectory(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{Include: []string{"admin/users/"}},
+		"templates/admin/list.hbs",
+		"templates/admin/users/edit.hbs",
+		"templates/admin/users/roles/show.hbs",
+	)
+
+	assertNames(t, []string{"admin/users/edit", "admin/users/roles/show"}, names)
+}
+
+func TestGlobGatherer_NonTemplateExtensions_AreSkipped(t *testing.T) {
+	names := gatherNames(t, glob.GlobGathererConfig{},
+		"templates/index.hbs",
+		"templates/notes.txt",
+	)
+
+	assertNames(t, []string{"index"}, names)
+}
+
+func TestGlobGatherer_SameNameDifferentExtensions_ReturnsCollisionError(t *testing.T) {
+	templateDir := "templates"
+	mockFileAccess := NewMemoryFileAccess()
+	mockFileAccess.RegisterFile("templates/index.hbs", []byte("hbs"), nil)
+	mockFileAccess.RegisterFile("templates/index.handlebars", []byte("handlebars"), nil)
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+		FileAccess:  mockFileAccess,
+		Extensions:  []string{".hbs", ".handlebars"},
+	})
+
+	_, err := gatherer.Gather()
+
+	var collisionErr *echorend.CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("expected CollisionError, got %v", err)
+	}
+	if len(collisionErr.Collisions) != 1 {
+		t.Fatalf("expected 1 collision, got %d", len(collisionErr.Collisions))
+	}
+	sources := collisionErr.Collisions[0].Sources
+	if len(sources) != 2 || sources[0] != "templates/index.hbs" || sources[1] != "templates/index.handlebars" {
+		t.Errorf("expected both source paths, got %v", sources)
+	}
+}
+
+func TestGlobGatherer_SameNameWithFirstWinsPolicy_KeepsFirstExtension(t *testing.T) {
+	templateDir := "templates"
+	mockFileAccess := NewMemoryFileAccess()
+	mockFileAccess.RegisterFile("templates/index.hbs", []byte("hbs"), nil)
+	mockFileAccess.RegisterFile("templates/index.handlebars", []byte("handlebars"), nil)
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir:     &templateDir,
+		FileAccess:      mockFileAccess,
+		Extensions:      []string{".hbs", ".handlebars"},
+		CollisionPolicy: echorend.CollisionFirstWins,
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 || templates[0].TemplateData != "hbs" {
+		t.Errorf("expected only the template with the first extension, got %v", templates)
+	}
+}
+
+func TestGlobGatherer_Gather_SetsSourceToFilePath(t *testing.T) {
+	templateDir := "templates"
+	mockFileAccess := NewMemoryFileAccess()
+	mockFileAccess.RegisterFile("templates/nested/file1.html", []byte("file1"), nil)
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+		FileAccess:  mockFileAccess,
+		Extensions:  []string{".html"},
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 || templates[0].Source != "templates/nested/file1.html" {
+		t.Errorf("expected source templates/nested/file1.html, got %v", templates)
+	}
+}
+
+func TestGlobGatherer_Gather_FillsSourceMetadata(t *testing.T) {
+	templateDir := "templates"
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockFileAccess := NewMemoryFileAccess()
+	mockFileAccess.RegisterFileModTime("templates/file1.html", []byte("file1"), modTime)
+	gatherer := glob.NewGlobGatherer(glob.GlobGathererConfig{
+		TemplateDir: &templateDir,
+		FileAccess:  mockFileAccess,
+		Extensions:  []string{".html"},
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("expected 1 template, got %d", len(templates))
+	}
+	template := templates[0]
+	if !template.ModTime.Equal(modTime) {
+		t.Errorf("expected mod time %v, got %v", modTime, template.ModTime)
+	}
+	if template.Hash != echorend.ContentHash("file1") {
+		t.Errorf("expected hash of the template data, got %s", template.Hash)
+	}
+	if template.Gatherer != "glob:templates" {
+		t.Errorf("expected gatherer glob:templates, got %s", template.Gatherer)
+	}
+}
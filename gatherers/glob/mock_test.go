@@ -1,41 +1,148 @@
 package glob_test
 
-type globResponse struct {
-	content []string
-	err     error
-}
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
 
 type fileResponse struct {
 	content []byte
+	modTime time.Time
 	err     error
 }
 
 // MemoryFileAccess is a mock implementation of FileAccess that stores its responses in memory.
+// Directories are implied by the paths of the registered files.
 type MemoryFileAccess struct {
-	globs map[string]globResponse
-	files map[string]fileResponse
+	files      map[string]fileResponse
+	walkErrors map[string]error
 }
 
 // NewMemoryFileAccess creates a new MemoryFileAccess.
 func NewMemoryFileAccess() *MemoryFileAccess {
 	return &MemoryFileAccess{
-		globs: make(map[string]globResponse),
-		files: make(map[string]fileResponse),
+		files:      make(map[string]fileResponse),
+		walkErrors: make(map[string]error),
+	}
+}
+
+func (m *MemoryFileAccess) WalkDir(root string, fn fs.WalkDirFunc) error {
+	err := m.walk(root, memoryDirEntry{name: root, dir: true}, fn)
+	if err == fs.SkipDir {
+		return nil
 	}
+	return err
 }
 
 func (m *MemoryFileAccess) Glob(pattern string) ([]string, error) {
-	return m.globs[pattern].content, m.globs[pattern].err
+	seen := make(map[string]bool)
+	matches := make([]string, 0)
+	for file := range m.files {
+		for candidate := file; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			if ok, err := path.Match(pattern, candidate); err != nil {
+				return nil, err
+			} else if ok && !seen[candidate] {
+				seen[candidate] = true
+				matches = append(matches, candidate)
+			}
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
 }
 
 func (m *MemoryFileAccess) ReadFile(filename string) ([]byte, error) {
 	return m.files[filename].content, m.files[filename].err
 }
 
-func (m *MemoryFileAccess) RegisterGlob(pattern string, content []string, err error) {
-	m.globs[pattern] = globResponse{content: content, err: err}
-}
-
 func (m *MemoryFileAccess) RegisterFile(filename string, content []byte, err error) {
 	m.files[filename] = fileResponse{content: content, err: err}
 }
+
+func (m *MemoryFileAccess) RegisterFileModTime(filename string, content []byte, modTime time.Time) {
+	m.files[filename] = fileResponse{content: content, modTime: modTime}
+}
+
+// RegisterWalkError makes the walk report err when it reaches the given path.
+func (m *MemoryFileAccess) RegisterWalkError(path string, err error) {
+	m.walkErrors[path] = err
+}
+
+func (m *MemoryFileAccess) walk(path string, entry memoryDirEntry, fn fs.WalkDirFunc) error {
+	if err, ok := m.walkErrors[path]; ok {
+		return fn(path, entry, err)
+	}
+	if err := fn(path, entry, nil); err != nil || !entry.dir {
+		return err
+	}
+
+	for _, child := range m.children(path) {
+		err := m.walk(path+"/"+child.name, child, fn)
+		if err == fs.SkipDir && child.dir {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemoryFileAccess) children(dir string) []memoryDirEntry {
+	seen := make(map[string]bool)
+	children := make([]memoryDirEntry, 0)
+	for file := range m.files {
+		if !strings.HasPrefix(file, dir+"/") {
+			continue
+		}
+		rest := strings.TrimPrefix(file, dir+"/")
+		name := strings.SplitN(rest, "/", 2)[0]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		children = append(children, memoryDirEntry{name: name, dir: name != rest, modTime: m.files[file].modTime})
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].name < children[j].name
+	})
+	return children
+}
+
+type memoryDirEntry struct {
+	name    string
+	dir     bool
+	modTime time.Time
+}
+
+func (e memoryDirEntry) Name() string               { return e.name }
+func (e memoryDirEntry) IsDir() bool                { return e.dir }
+func (e memoryDirEntry) Type() fs.FileMode          { return e.mode().Type() }
+func (e memoryDirEntry) Info() (fs.FileInfo, error) { return e, nil }
+func (e memoryDirEntry) Size() int64                { return 0 }
+func (e memoryDirEntry) ModTime() time.Time         { return e.modTime }
+func (e memoryDirEntry) Sys() interface{}           { return nil }
+func (e memoryDirEntry) Mode() fs.FileMode          { return e.mode() }
+
+func (e memoryDirEntry) mode() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir
+	}
+	return 0
+}
+
+// GlobFileAccess hides the WalkDir of a MemoryFileAccess, so gatherers have to fall back to walking with Glob.
+type GlobFileAccess struct {
+	memory *MemoryFileAccess
+}
+
+func (g GlobFileAccess) Glob(pattern string) ([]string, error) {
+	return g.memory.Glob(pattern)
+}
+
+func (g GlobFileAccess) ReadFile(filename string) ([]byte, error) {
+	return g.memory.ReadFile(filename)
+}
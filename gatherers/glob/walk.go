@@ -0,0 +1,77 @@
+package glob
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BlindGarret/echorend/externals"
+)
+
+// walkDir walks the tree at root with the FileAccess's own WalkDir when it has one, and otherwise with Glob.
+func walkDir(fileAccess externals.FileAccess, root string, fn fs.WalkDirFunc) error {
+	if walker, ok := fileAccess.(externals.DirWalker); ok {
+		return walker.WalkDir(root, fn)
+	}
+	err := globWalk(fileAccess, root, globDirEntry{name: filepath.Base(root), dir: true}, fn)
+	if err == fs.SkipDir {
+		return nil
+	}
+	return err
+}
+
+// globWalk walks a tree using only Glob, in lexical order as filepath.WalkDir does. Glob can't tell files from
+// directories, so an entry is a directory when it has entries of its own, and mod times are unknown.
+func globWalk(fileAccess externals.FileAccess, path string, entry globDirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, entry, nil); err != nil || !entry.dir {
+		return err
+	}
+
+	children, err := fileAccess.Glob(escapeGlob(path) + "/*")
+	if err != nil {
+		return fn(path, entry, err)
+	}
+	for _, child := range children {
+		grandchildren, err := fileAccess.Glob(escapeGlob(child) + "/*")
+		if err != nil {
+			return err
+		}
+		err = globWalk(fileAccess, child, globDirEntry{name: filepath.Base(child), dir: len(grandchildren) > 0}, fn)
+		if err == fs.SkipDir {
+			if len(grandchildren) > 0 {
+				continue
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeGlob quotes the pattern characters in path with character classes, which unlike backslashes work on Windows.
+func escapeGlob(path string) string {
+	return strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]").Replace(path)
+}
+
+type globDirEntry struct {
+	name string
+	dir  bool
+}
+
+func (e globDirEntry) Name() string               { return e.name }
+func (e globDirEntry) IsDir() bool                { return e.dir }
+func (e globDirEntry) Type() fs.FileMode          { return e.Mode().Type() }
+func (e globDirEntry) Info() (fs.FileInfo, error) { return e, nil }
+func (e globDirEntry) Size() int64                { return 0 }
+func (e globDirEntry) ModTime() time.Time         { return time.Time{} }
+func (e globDirEntry) Sys() interface{}           { return nil }
+
+func (e globDirEntry) Mode() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+// Package extensions matches template files by their extension for the file system gatherers, and orders the
+// templates they gather so collision policies prefer the extensions listed first.
+package extensions
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/BlindGarret/echorend"
+)
+
+// Has reports whether file has one of the extensions.
+func Has(file string, extensions []string) bool {
+	return Index(file, extensions) >= 0
+}
+
+// Index returns the position in extensions of the first one file has, or -1 if it has none.
+func Index(file string, extensions []string) int {
+	for i, extension := range extensions {
+		if strings.HasSuffix(file, extension) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Sort orders templates by the position of their source's extension in extensions, keeping templates with the
+// same extension in the order they were gathered. Gatherers walk in path order, so with CollisionFirstWins the
+// template with the extension listed first wins a collision.
+func Sort(templates []echorend.RawTemplateData, extensions []string) {
+	sort.SliceStable(templates, func(i, j int) bool {
+		return Index(templates[i].Source, extensions) < Index(templates[j].Source, extensions)
+	})
+}
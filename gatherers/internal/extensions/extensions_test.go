@@ -0,0 +1,42 @@
+package extensions_test
+
+import (
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/internal/extensions"
+)
+
+func TestIndex_Extensions_ReturnsPositionOfFirstMatch(t *testing.T) {
+	cases := []struct {
+		file string
+		want int
+	}{
+		{"views/index.hbs", 0},
+		{"views/index.handlebars", 1},
+		{"views/index.html", -1},
+	}
+	for _, c := range cases {
+		if got := extensions.Index(c.file, []string{".hbs", ".handlebars"}); got != c.want {
+			t.Errorf("%s: expected %d, got %d", c.file, c.want, got)
+		}
+	}
+}
+
+func TestSort_MixedExtensions_OrdersByExtensionThenGatheredOrder(t *testing.T) {
+	templates := []echorend.RawTemplateData{
+		{Source: "a.handlebars"},
+		{Source: "b.hbs"},
+		{Source: "c.handlebars"},
+		{Source: "d.hbs"},
+	}
+
+	extensions.Sort(templates, []string{".hbs", ".handlebars"})
+
+	expected := []string{"b.hbs", "d.hbs", "a.handlebars", "c.handlebars"}
+	for i, template := range templates {
+		if template.Source != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, templates)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package iofs
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/internal/extensions"
+)
+
+// ErrNoFS is returned when gathering with a FSGatherer configured without an FS.
+var ErrNoFS = errors.New("no file system to gather templates from")
+
+// FSGathererConfig is a configuration struct for creating a FSGatherer.
+type FSGathererConfig struct {
+	FS              fs.FS
+	TemplateDir     *string
+	IncludeTLDInKey bool
+	Extensions      []string
+	// CollisionPolicy decides what happens when templates resolve to the same name, as for the glob gatherer, with
+	// the extension listed first in Extensions winning under CollisionFirstWins.
+	CollisionPolicy echorend.CollisionPolicy
+}
+
+// FSGatherer is a gatherer for getting templates from any fs.FS, such as an embed.FS or os.DirFS.
+// Templates are named the same way as the GlobGatherer names them.
+type FSGatherer struct {
+	config FSGathererConfig
+}
+
+func NewFSGatherer(config FSGathererConfig) *FSGatherer {
+	return &FSGatherer{
+		config: defaultFSGathererConfig(config),
+	}
+}
+
+// MustGather attempts to gather templates from the file system. If an error occurs, it panics.
+func (g *FSGatherer) MustGather() []echorend.RawTemplateData {
+	templates, err := g.Gather()
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// Gather walks the file system below the template directory and gets every template with a matching extension.
+func (g *FSGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	if g.config.FS == nil {
+		return nil, ErrNoFS
+	}
+	templates := make([]echorend.RawTemplateData, 0)
+	root := path.Clean(*g.config.TemplateDir)
+
+	err := fs.WalkDir(g.config.FS, root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !extensions.Has(file, g.config.Extensions) {
+			return nil
+		}
+
+		templateName := getTemplateName(file, root)
+		if g.config.IncludeTLDInKey {
+			templateName = *g.config.TemplateDir + "/" + templateName
+		}
+		bs, err := fs.ReadFile(g.config.FS, file)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		templates = append(templates, echorend.RawTemplateData{
+			TemplateName: templateName,
+			TemplateData: string(bs),
+			Source:       file,
+			ModTime:      info.ModTime(),
+			Hash:         echorend.ContentHash(string(bs)),
+			Gatherer:     "iofs:" + *g.config.TemplateDir,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extensions.Sort(templates, g.config.Extensions)
+	return echorend.ResolveCollisions(templates, g.config.CollisionPolicy)
+}
+
+func defaultFSGathererConfig(config FSGathererConfig) FSGathererConfig {
+	if config.TemplateDir == nil {
+		tld := "templates/views"
+		config.TemplateDir = &tld
+	}
+
+	return config
+}
+
+func getTemplateName(file string, root string) string {
+	if root != "." {
+		file = strings.TrimPrefix(file, root+"/")
+	}
+	return file[:len(file)-len(path.Ext(file))]
+}
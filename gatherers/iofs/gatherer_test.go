@@ -0,0 +1,286 @@
+package iofs_test
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/iofs"
+)
+
+//go:embed testdata
+var testdata embed.FS
+
+// sortedByName sorts the templates by name, keeping only their names and data for comparison.
+func sortedByName(templates []echorend.RawTemplateData) []echorend.RawTemplateData {
+	sorted := make([]echorend.RawTemplateData, 0, len(templates))
+	for _, template := range templates {
+		sorted = append(sorted, echorend.RawTemplateData{
+			TemplateName: template.TemplateName,
+			TemplateData: template.TemplateData,
+		})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].TemplateName < sorted[j].TemplateName
+	})
+	return sorted
+}
+
+func TestFSGatherer_Interface_CompliesWithRawTemplateGatherer(t *testing.T) {
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{})
+	_, ok := interface{}(gatherer).(echorend.RawTemplateGatherer)
+	if !ok {
+		t.Fatalf("FSGatherer does not comply with RawTemplateGatherer interface")
+	}
+}
+
+func TestFSGatherer_HappyPathNoTLD_ReturnsAsExpected(t *testing.T) {
+	templateDir := "templates"
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS: fstest.MapFS{
+			"templates/file1.html":                {Data: []byte("file1")},
+			"templates/nested/file2.html":         {Data: []byte("file2")},
+			"templates/nested/deeper/file3.html":  {Data: []byte("file3")},
+			"templates/nested/deeper/ignored.txt": {Data: []byte("ignored")},
+			"other/file4.html":                    {Data: []byte("file4")},
+		},
+		TemplateDir: &templateDir,
+		Extensions:  []string{".html"},
+	})
+	expected := []echorend.RawTemplateData{
+		{TemplateName: "file1", TemplateData: "file1"},
+		{TemplateName: "nested/deeper/file3", TemplateData: "file3"},
+		{TemplateName: "nested/file2", TemplateData: "file2"},
+	}
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(sortedByName(templates), expected) {
+		t.Errorf("expected %v, got %v", expected, templates)
+	}
+}
+
+func TestFSGatherer_HappyPathWithTLD_ReturnsAsExpected(t *testing.T) {
+	templateDir := "templates"
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS: fstest.MapFS{
+			"templates/file1.html":        {Data: []byte("file1")},
+			"templates/nested/file2.html": {Data: []byte("file2")},
+		},
+		TemplateDir:     &templateDir,
+		IncludeTLDInKey: true,
+		Extensions:      []string{".html"},
+	})
+	expected := []echorend.RawTemplateData{
+		{TemplateName: "templates/file1", TemplateData: "file1"},
+		{TemplateName: "templates/nested/file2", TemplateData: "file2"},
+	}
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(sortedByName(templates), expected) {
+		t.Errorf("expected %v, got %v", expected, templates)
+	}
+}
+
+func TestFSGatherer_RootTemplateDir_NamesFromFSRoot(t *testing.T) {
+	templateDir := "."
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS: fstest.MapFS{
+			"file1.hbs":        {Data: []byte("file1")},
+			"nested/file2.hbs": {Data: []byte("file2")},
+		},
+		TemplateDir: &templateDir,
+		Extensions:  []string{".hbs"},
+	})
+	expected := []echorend.RawTemplateData{
+		{TemplateName: "file1", TemplateData: "file1"},
+		{TemplateName: "nested/file2", TemplateData: "file2"},
+	}
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(sortedByName(templates), expected) {
+		t.Errorf("expected %v, got %v", expected, templates)
+	}
+}
+
+func TestFSGatherer_EmbedDirAndMapFS_GatherIdentically(t *testing.T) {
+	templateDir := "testdata/templates/views"
+	dirTemplateDir := "templates/views"
+	mapFS := fstest.MapFS{
+		"templates/views/index.hbs":              {Data: []byte("index")},
+		"templates/views/nested/show.hbs":        {Data: []byte("show")},
+		"templates/views/nested/deeper/edit.hbs": {Data: []byte("edit")},
+		"templates/views/notes.txt":              {Data: []byte("ignored")},
+	}
+	gatherers := map[string]*iofs.FSGatherer{
+		"embed.FS": iofs.NewFSGatherer(iofs.FSGathererConfig{FS: testdata, TemplateDir: &templateDir, Extensions: []string{".hbs"}}),
+		"os.DirFS": iofs.NewFSGatherer(iofs.FSGathererConfig{FS: os.DirFS("testdata"), TemplateDir: &dirTemplateDir, Extensions: []string{".hbs"}}),
+		"MapFS":    iofs.NewFSGatherer(iofs.FSGathererConfig{FS: mapFS, TemplateDir: &dirTemplateDir, Extensions: []string{".hbs"}}),
+	}
+	expected := []echorend.RawTemplateData{
+		{TemplateName: "index", TemplateData: "index"},
+		{TemplateName: "nested/deeper/edit", TemplateData: "edit"},
+		{TemplateName: "nested/show", TemplateData: "show"},
+	}
+
+	for name, gatherer := range gatherers {
+		templates, err := gatherer.Gather()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !reflect.DeepEqual(sortedByName(templates), expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, templates)
+		}
+	}
+}
+
+func TestFSGatherer_MissingTemplateDir_ReturnsError(t *testing.T) {
+	templateDir := "missing"
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS:          fstest.MapFS{},
+		TemplateDir: &templateDir,
+		Extensions:  []string{".html"},
+	})
+
+	_, err := gatherer.Gather()
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected %v, got %v", fs.ErrNotExist, err)
+	}
+}
+
+func TestFSGatherer_NilFS_ReturnsError(t *testing.T) {
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		Extensions: []string{".html"},
+	})
+
+	_, err := gatherer.Gather()
+
+	if !errors.Is(err, iofs.ErrNoFS) {
+		t.Fatalf("expected %v, got %v", iofs.ErrNoFS, err)
+	}
+}
+
+func TestFSGatherer_ErrorMustGather_Panics(t *testing.T) {
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS:         fstest.MapFS{},
+		Extensions: []string{".html"},
+	})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic, got nil")
+		}
+	}()
+
+	gatherer.MustGather()
+}
+
+func TestFSGatherer_Gather_SetsSourceToFilePath(t *testing.T) {
+	templateDir := "templates"
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS:          fstest.MapFS{"templates/nested/file1.html": {Data: []byte("file1")}},
+		TemplateDir: &templateDir,
+		Extensions:  []string{".html"},
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 || templates[0].Source != "templates/nested/file1.html" {
+		t.Errorf("expected source templates/nested/file1.html, got %v", templates)
+	}
+}
+
+func TestFSGatherer_SameNameDifferentExtensions_ReturnsCollisionError(t *testing.T) {
+	templateDir := "templates"
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS: fstest.MapFS{
+			"templates/index.hbs":        {Data: []byte("hbs")},
+			"templates/index.handlebars": {Data: []byte("handlebars")},
+		},
+		TemplateDir: &templateDir,
+		Extensions:  []string{".hbs", ".handlebars"},
+	})
+
+	_, err := gatherer.Gather()
+
+	var collisionErr *echorend.CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("expected CollisionError, got %v", err)
+	}
+	if len(collisionErr.Collisions) != 1 || len(collisionErr.Collisions[0].Sources) != 2 {
+		t.Errorf("expected one collision with two sources, got %v", collisionErr.Collisions)
+	}
+}
+
+func TestFSGatherer_SameNameWithFirstWinsPolicy_KeepsFirstExtension(t *testing.T) {
+	templateDir := "templates"
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS: fstest.MapFS{
+			"templates/index.hbs":        {Data: []byte("hbs")},
+			"templates/index.handlebars": {Data: []byte("handlebars")},
+		},
+		TemplateDir:     &templateDir,
+		Extensions:      []string{".hbs", ".handlebars"},
+		CollisionPolicy: echorend.CollisionFirstWins,
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 || templates[0].TemplateData != "hbs" {
+		t.Errorf("expected only the template with the first extension, got %v", templates)
+	}
+}
+
+func TestFSGatherer_Gather_FillsSourceMetadata(t *testing.T) {
+	templateDir := "templates"
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	gatherer := iofs.NewFSGatherer(iofs.FSGathererConfig{
+		FS:          fstest.MapFS{"templates/file1.html": {Data: []byte("file1"), ModTime: modTime}},
+		TemplateDir: &templateDir,
+		Extensions:  []string{".html"},
+	})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("expected 1 template, got %d", len(templates))
+	}
+	template := templates[0]
+	if !template.ModTime.Equal(modTime) {
+		t.Errorf("expected mod time %v, got %v", modTime, template.ModTime)
+	}
+	if template.Hash != echorend.ContentHash("file1") {
+		t.Errorf("expected hash of the template data, got %s", template.Hash)
+	}
+	if template.Gatherer != "iofs:templates" {
+		t.Errorf("expected gatherer iofs:templates, got %s", template.Gatherer)
+	}
+}
@@ -0,0 +1,55 @@
+package namespace
+
+import (
+	"github.com/BlindGarret/echorend"
+)
+
+// NamespacedGatherer puts every template of another gatherer into a namespace, so feature modules can
+// contribute templates without their names colliding, such as invoice/show becoming billing:invoice/show.
+type NamespacedGatherer struct {
+	namespace string
+	gatherer  echorend.RawTemplateGatherer
+}
+
+func NewNamespacedGatherer(namespace string, gatherer echorend.RawTemplateGatherer) *NamespacedGatherer {
+	return &NamespacedGatherer{
+		namespace: namespace,
+		gatherer:  gatherer,
+	}
+}
+
+// MustGather attempts to gather the namespaced templates. If an error occurs, it panics.
+func (g *NamespacedGatherer) MustGather() []echorend.RawTemplateData {
+	templates, err := g.Gather()
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// Gather gets the wrapped gatherer's templates with their names qualified by the namespace.
+func (g *NamespacedGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	templates, err := g.gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	return Apply(g.namespace, templates), nil
+}
+
+// WatchDirs returns the wrapped gatherer's directories, if it is watchable.
+func (g *NamespacedGatherer) WatchDirs() []string {
+	if watchable, ok := g.gatherer.(echorend.WatchableGatherer); ok {
+		return watchable.WatchDirs()
+	}
+	return nil
+}
+
+// Apply returns copies of the templates with their names qualified by the namespace.
+func Apply(namespace string, templates []echorend.RawTemplateData) []echorend.RawTemplateData {
+	namespaced := make([]echorend.RawTemplateData, 0, len(templates))
+	for _, template := range templates {
+		template.TemplateName = echorend.QualifiedName(namespace, template.TemplateName)
+		namespaced = append(namespaced, template)
+	}
+	return namespaced
+}
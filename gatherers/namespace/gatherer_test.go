@@ -0,0 +1,48 @@
+package namespace_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/namespace"
+)
+
+func TestNamespacedGathererGather_Templates_QualifiesNames(t *testing.T) {
+	gatherer := namespace.NewNamespacedGatherer("billing", &MockTemplateGatherer{templates: []echorend.RawTemplateData{
+		{TemplateName: "invoice/show", TemplateData: "show", Source: "billing/views/invoice/show.hbs"},
+	}})
+
+	templates, err := gatherer.Gather()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []echorend.RawTemplateData{
+		{TemplateName: "billing:invoice/show", TemplateData: "show", Source: "billing/views/invoice/show.hbs"},
+	}
+	if !reflect.DeepEqual(templates, expected) {
+		t.Errorf("Expected %v, got %v", expected, templates)
+	}
+}
+
+func TestNamespacedGathererGather_Error_ReturnsError(t *testing.T) {
+	gatherer := namespace.NewNamespacedGatherer("billing", &MockTemplateGatherer{err: errors.New("broken")})
+
+	_, err := gatherer.Gather()
+
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestNamespacedGathererWatchDirs_WatchableGatherer_ReturnsItsDirs(t *testing.T) {
+	gatherer := namespace.NewNamespacedGatherer("billing", &MockTemplateGatherer{dirs: []string{"billing/views"}})
+
+	dirs := gatherer.WatchDirs()
+
+	if !reflect.DeepEqual(dirs, []string{"billing/views"}) {
+		t.Errorf("Expected billing/views, got %v", dirs)
+	}
+}
@@ -0,0 +1,24 @@
+package namespace_test
+
+import "github.com/BlindGarret/echorend"
+
+type MockTemplateGatherer struct {
+	templates []echorend.RawTemplateData
+	dirs      []string
+	err       error
+}
+
+func (m *MockTemplateGatherer) MustGather() []echorend.RawTemplateData {
+	if m.err != nil {
+		panic(m.err)
+	}
+	return m.templates
+}
+
+func (m *MockTemplateGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	return m.templates, m.err
+}
+
+func (m *MockTemplateGatherer) WatchDirs() []string {
+	return m.dirs
+}
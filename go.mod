@@ -1,10 +1,15 @@
 module github.com/BlindGarret/echorend
 
-go 1.17.11
+go 1.18
 
-require github.com/labstack/echo/v4 v4.12.0
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/labstack/echo/v4 v4.12.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
+	github.com/golang-jwt/jwt v3.2.2+incompatible // indirect
 	github.com/labstack/gommon v0.4.2 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -13,6 +18,7 @@ require (
 	golang.org/x/net v0.24.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
+	golang.org/x/time v0.5.0 // indirect
 )
 
 require (
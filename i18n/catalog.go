@@ -0,0 +1,212 @@
+// Package i18n loads translated message catalogs and picks the locale of each request, for renderers to
+// translate templates with.
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CountArg is the argument which picks the plural form of a message, and can be interpolated like any other.
+const CountArg = "count"
+
+// Message is a translated message. Singular messages only have the "other" form, plural messages have a form
+// for each plural category the locale uses, such as "one" and "other".
+type Message struct {
+	Forms map[string]string
+}
+
+// Text returns a message with a single form.
+func Text(text string) Message {
+	return Message{Forms: map[string]string{"other": text}}
+}
+
+// Catalog holds the messages of every locale. It is safe to load into while translating.
+type Catalog struct {
+	defaultLocale string
+
+	mutex    sync.RWMutex
+	messages map[string]map[string]Message
+}
+
+// NewCatalog creates an empty catalog. Messages missing from a locale fall back to the default locale.
+func NewCatalog(defaultLocale string) *Catalog {
+	return &Catalog{
+		defaultLocale: defaultLocale,
+		messages:      make(map[string]map[string]Message),
+	}
+}
+
+// DefaultLocale returns the locale used when no other matches.
+func (c *Catalog) DefaultLocale() string {
+	return c.defaultLocale
+}
+
+// Add sets the message for a key in a locale, replacing any message already there.
+func (c *Catalog) Add(locale, key string, message Message) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]Message)
+	}
+	c.messages[locale][key] = message
+}
+
+// Locales returns every locale with messages, sorted.
+func (c *Catalog) Locales() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Match returns the first candidate locale the catalog has, trying each candidate exactly and then by its base
+// language, so "fr-CA" matches "fr". It returns the default locale if none match.
+func (c *Catalog) Match(candidates ...string) string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, candidate := range candidates {
+		if locale, ok := c.find(candidate); ok {
+			return locale
+		}
+	}
+	return c.defaultLocale
+}
+
+func (c *Catalog) find(candidate string) (string, bool) {
+	candidate = strings.ReplaceAll(candidate, "_", "-")
+	if candidate == "" {
+		return "", false
+	}
+	for locale := range c.messages {
+		if strings.EqualFold(locale, candidate) {
+			return locale, true
+		}
+	}
+	base := baseLanguage(candidate)
+	for locale := range c.messages {
+		if strings.EqualFold(locale, base) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Translate returns the message for key in a locale, with its {name} placeholders replaced by args.
+// If args has a CountArg, it picks the plural form. Missing messages fall back to the locale's base language,
+// then the default locale, and finally to the key itself.
+func (c *Catalog) Translate(locale, key string, args map[string]interface{}) string {
+	message, locale, ok := c.lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	text, ok := message.Forms["other"]
+	if count, hasCount := args[CountArg]; hasCount {
+		if n, isNumber := toFloat(count); isNumber {
+			if form, hasForm := message.Forms[PluralCategory(locale, n)]; hasForm {
+				text, ok = form, true
+			}
+		}
+	}
+	if !ok {
+		text = fallbackForm(message.Forms)
+	}
+	return interpolate(text, args)
+}
+
+// fallbackForms is the order forms are tried in when a message has neither the count's form nor "other".
+var fallbackForms = []string{"many", "few", "two", "one", "zero"}
+
+// fallbackForm picks a message's form in a fixed order, so the same message always renders the same way.
+func fallbackForm(forms map[string]string) string {
+	for _, category := range fallbackForms {
+		if form, ok := forms[category]; ok {
+			return form
+		}
+	}
+	categories := make([]string, 0, len(forms))
+	for category := range forms {
+		categories = append(categories, category)
+	}
+	if len(categories) == 0 {
+		return ""
+	}
+	sort.Strings(categories)
+	return forms[categories[0]]
+}
+
+func (c *Catalog) lookup(locale, key string) (Message, string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, candidate := range []string{locale, baseLanguage(locale), c.defaultLocale} {
+		if message, ok := c.messages[candidate][key]; ok {
+			return message, candidate, true
+		}
+	}
+	return Message{}, "", false
+}
+
+// interpolate replaces each {name} with its argument, leaving placeholders without an argument untouched.
+func interpolate(text string, args map[string]interface{}) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(text[:start])
+		if value, ok := args[text[start+1:end]]; ok {
+			b.WriteString(fmt.Sprint(value))
+		} else {
+			b.WriteString(text[start : end+1])
+		}
+		text = text[end+1:]
+	}
+	b.WriteString(text)
+	return b.String()
+}
+
+func baseLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	if s, ok := value.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
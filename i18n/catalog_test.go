@@ -0,0 +1,122 @@
+package i18n_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/BlindGarret/echorend/i18n"
+)
+
+func newTestCatalog() *i18n.Catalog {
+	catalog := i18n.NewCatalog("en")
+	catalog.Add("en", "greeting", i18n.Text("Hello {name}"))
+	catalog.Add("en", "only-en", i18n.Text("English only"))
+	catalog.Add("en", "items", i18n.Message{Forms: map[string]string{"one": "{count} item", "other": "{count} items"}})
+	catalog.Add("fr", "greeting", i18n.Text("Bonjour {name}"))
+	catalog.Add("fr", "items", i18n.Message{Forms: map[string]string{"one": "{count} article", "other": "{count} articles"}})
+	catalog.Add("fr-CA", "greeting", i18n.Text("Allo {name}"))
+	return catalog
+}
+
+func TestCatalogTranslate_Interpolation_ReplacesArgs(t *testing.T) {
+	catalog := newTestCatalog()
+
+	got := catalog.Translate("fr", "greeting", map[string]interface{}{"name": "Ana"})
+
+	if got != "Bonjour Ana" {
+		t.Errorf("Expected Bonjour Ana, got %s", got)
+	}
+}
+
+func TestCatalogTranslate_MissingArg_LeavesPlaceholder(t *testing.T) {
+	catalog := newTestCatalog()
+
+	got := catalog.Translate("en", "greeting", nil)
+
+	if got != "Hello {name}" {
+		t.Errorf("Expected placeholder kept, got %s", got)
+	}
+}
+
+func TestCatalogTranslate_Count_PicksPluralFormForLocale(t *testing.T) {
+	catalog := newTestCatalog()
+	cases := []struct {
+		locale string
+		count  interface{}
+		want   string
+	}{
+		{"en", 1, "1 item"},
+		{"en", 0, "0 items"},
+		{"en", 2.0, "2 items"},
+		{"fr", 0, "0 article"},
+		{"fr", 1, "1 article"},
+		{"fr", 2, "2 articles"},
+		{"fr-CA", 0, "0 article"},
+	}
+	for _, c := range cases {
+		got := catalog.Translate(c.locale, "items", map[string]interface{}{"count": c.count})
+		if got != c.want {
+			t.Errorf("%s with %v: expected %q, got %q", c.locale, c.count, c.want, got)
+		}
+	}
+}
+
+func TestCatalogTranslate_Missing_FallsBackThroughLocales(t *testing.T) {
+	catalog := newTestCatalog()
+
+	regional := catalog.Translate("fr-CA", "greeting", map[string]interface{}{"name": "Ana"})
+	base := catalog.Translate("fr-CA", "items", map[string]interface{}{"count": 3})
+	fallback := catalog.Translate("fr", "only-en", nil)
+	key := catalog.Translate("fr", "nowhere", nil)
+
+	if regional != "Allo Ana" || base != "3 articles" || fallback != "English only" || key != "nowhere" {
+		t.Errorf("Unexpected fallbacks %q, %q, %q, %q", regional, base, fallback, key)
+	}
+}
+
+func TestCatalogTranslate_NoOtherForm_FallsBackInFixedOrder(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+	catalog.Add("en", "items", i18n.Message{Forms: map[string]string{"zero": "none", "one": "one item", "few": "a few items"}})
+
+	for i := 0; i < 20; i++ {
+		if got := catalog.Translate("en", "items", map[string]interface{}{"count": 5}); got != "a few items" {
+			t.Fatalf("Expected the few form, got %q", got)
+		}
+	}
+}
+
+func TestCatalogMatch_Candidates_ReturnsFirstSupported(t *testing.T) {
+	catalog := newTestCatalog()
+	cases := []struct {
+		candidates []string
+		want       string
+	}{
+		{[]string{"de", "fr-ca"}, "fr-CA"},
+		{[]string{"fr_BE"}, "fr"},
+		{[]string{"de"}, "en"},
+		{nil, "en"},
+	}
+	for _, c := range cases {
+		if got := catalog.Match(c.candidates...); got != c.want {
+			t.Errorf("%v: expected %s, got %s", c.candidates, c.want, got)
+		}
+	}
+}
+
+func TestCatalog_ConcurrentAddAndTranslate_IsSafe(t *testing.T) {
+	catalog := newTestCatalog()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			catalog.Add("de", "greeting", i18n.Text("Hallo {name}"))
+		}()
+		go func() {
+			defer wg.Done()
+			_ = catalog.Translate("de", "greeting", map[string]interface{}{"name": "Ana"})
+			_ = catalog.Match("de")
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pluralCategories are the keys which make a JSON or YAML object a plural message rather than nested keys.
+var pluralCategories = map[string]bool{
+	"zero":  true,
+	"one":   true,
+	"two":   true,
+	"few":   true,
+	"many":  true,
+	"other": true,
+}
+
+// LoadJSON adds the messages of a locale from JSON. Nested objects become dotted keys, and an object keyed by
+// plural categories is a plural message.
+//
+//	{"cart": {"title": "Your cart", "items": {"one": "{count} item", "other": "{count} items"}}}
+func (c *Catalog) LoadJSON(locale string, data []byte) error {
+	var messages map[string]interface{}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("loading %s messages: %w", locale, err)
+	}
+	return c.loadTree(locale, "", messages)
+}
+
+// LoadYAML adds the messages of a locale from YAML, laid out the same way as LoadJSON.
+func (c *Catalog) LoadYAML(locale string, data []byte) error {
+	var messages map[string]interface{}
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("loading %s messages: %w", locale, err)
+	}
+	return c.loadTree(locale, "", messages)
+}
+
+// LoadFS adds every catalog file in dir, named by its locale, such as fr.json, pt-BR.yaml or de.po.
+// Other files are ignored.
+func (c *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := path.Ext(entry.Name())
+		var load func(string, []byte) error
+		switch ext {
+		case ".json":
+			load = c.LoadJSON
+		case ".yaml", ".yml":
+			load = c.LoadYAML
+		case ".po":
+			load = c.LoadPO
+		default:
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := load(strings.TrimSuffix(entry.Name(), ext), data); err != nil {
+			return fmt.Errorf("%s: %w", path.Join(dir, entry.Name()), err)
+		}
+	}
+	return nil
+}
+
+func (c *Catalog) loadTree(locale, prefix string, tree map[string]interface{}) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			c.Add(locale, key, Text(v))
+		case map[string]interface{}:
+			if forms, ok := pluralForms(v); ok {
+				c.Add(locale, key, Message{Forms: forms})
+				continue
+			}
+			if err := c.loadTree(locale, key, v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("loading %s messages: %s must be a string or an object, got %v", locale, key, value)
+		}
+	}
+	return nil
+}
+
+func pluralForms(tree map[string]interface{}) (map[string]string, bool) {
+	if _, ok := tree["other"]; !ok {
+		return nil, false
+	}
+	forms := make(map[string]string)
+	for category, value := range tree {
+		text, ok := value.(string)
+		if !ok || !pluralCategories[category] {
+			return nil, false
+		}
+		forms[category] = text
+	}
+	return forms, true
+}
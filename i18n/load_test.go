@@ -0,0 +1,180 @@
+package i18n_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/BlindGarret/echorend/i18n"
+)
+
+func TestCatalogLoadJSON_NestedAndPlural_LoadsDottedKeys(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadJSON("en", []byte(`{"nav": {"home": "Home"}, "items": {"one": "{count} item", "other": "{count} items"}}`))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := catalog.Translate("en", "nav.home", nil); got != "Home" {
+		t.Errorf("Expected Home, got %s", got)
+	}
+	if got := catalog.Translate("en", "items", map[string]interface{}{"count": 1}); got != "1 item" {
+		t.Errorf("Expected 1 item, got %s", got)
+	}
+}
+
+func TestCatalogLoadJSON_NonStringValue_ReturnsError(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadJSON("en", []byte(`{"count": 3}`))
+
+	if err == nil {
+		t.Errorf("Expected an error for a number message")
+	}
+}
+
+func TestCatalogLoadYAML_NestedAndPlural_LoadsDottedKeys(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadYAML("fr", []byte("nav:\n  home: Accueil\nitems:\n  one: \"{count} article\"\n  other: \"{count} articles\"\n"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := catalog.Translate("fr", "nav.home", nil); got != "Accueil" {
+		t.Errorf("Expected Accueil, got %s", got)
+	}
+	if got := catalog.Translate("fr", "items", map[string]interface{}{"count": 0}); got != "0 article" {
+		t.Errorf("Expected 0 article, got %s", got)
+	}
+}
+
+func TestCatalogLoadPO_Entries_LoadsTranslations(t *testing.T) {
+	data, _ := os.ReadFile("testdata/locales/de.po")
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadPO("de", data)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cases := map[string]string{
+		"nav.home":     "Startseite",
+		"long":         "Eine lange Nachricht",
+		"draft":        "draft",
+		"untranslated": "untranslated",
+	}
+	for key, want := range cases {
+		if got := catalog.Translate("de", key, nil); got != want {
+			t.Errorf("%s: expected %q, got %q", key, want, got)
+		}
+	}
+	if got := catalog.Translate("de", "cart.items", map[string]interface{}{"count": 2}); got != "2 Artikel" {
+		t.Errorf("Expected 2 Artikel, got %s", got)
+	}
+}
+
+func poWithPluralForms(pluralForms string, forms ...string) []byte {
+	var b strings.Builder
+	b.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	if pluralForms != "" {
+		fmt.Fprintf(&b, "\"Plural-Forms: %s\\n\"\n", pluralForms)
+	}
+	b.WriteString("\nmsgid \"files\"\nmsgid_plural \"files\"\n")
+	for i, form := range forms {
+		fmt.Fprintf(&b, "msgstr[%d] \"{count} %s\"\n", i, form)
+	}
+	return []byte(b.String())
+}
+
+func TestCatalogLoadPO_PluralFormsHeader_LabelsFormsForLanguage(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+	cs := poWithPluralForms("nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;", "soubor", "soubory", "souborů")
+	ru := poWithPluralForms("nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);", "файл", "файла", "файлов")
+
+	if err := catalog.LoadPO("cs", cs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := catalog.LoadPO("ru", ru); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cases := []struct {
+		locale string
+		count  int
+		want   string
+	}{
+		{"cs", 1, "1 soubor"},
+		{"cs", 3, "3 soubory"},
+		{"cs", 5, "5 souborů"},
+		{"ru", 21, "21 файл"},
+		{"ru", 3, "3 файла"},
+		{"ru", 11, "11 файлов"},
+	}
+	for _, c := range cases {
+		if got := catalog.Translate(c.locale, "files", map[string]interface{}{"count": c.count}); got != c.want {
+			t.Errorf("%s %d: expected %q, got %q", c.locale, c.count, c.want, got)
+		}
+	}
+}
+
+func TestCatalogLoadPO_UnknownPluralForms_ReturnsError(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadPO("ar", poWithPluralForms("nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);", "a", "b", "c", "d", "e", "f"))
+
+	if err == nil || !strings.Contains(err.Error(), "Plural-Forms") {
+		t.Errorf("Expected an unsupported Plural-Forms error, got %v", err)
+	}
+}
+
+func TestCatalogLoadPO_ThreeFormsWithoutHeader_ReturnsError(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadPO("ro", poWithPluralForms("", "fișier", "fișiere", "de fișiere"))
+
+	if err == nil || !strings.Contains(err.Error(), "Plural-Forms") {
+		t.Errorf("Expected an error asking for a Plural-Forms header, got %v", err)
+	}
+}
+
+func TestCatalogLoadPO_BadLine_ReturnsError(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadPO("de", []byte("msgid \"a\"\nmsgstr unquoted\n"))
+
+	if err == nil {
+		t.Errorf("Expected an error for an unquoted string")
+	}
+}
+
+func TestCatalogLoadFS_Directory_LoadsEveryFormat(t *testing.T) {
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadFS(os.DirFS("testdata"), "locales")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	locales := catalog.Locales()
+	if len(locales) != 3 || locales[0] != "de" || locales[1] != "en" || locales[2] != "fr" {
+		t.Errorf("Expected de, en and fr, got %v", locales)
+	}
+	if got := catalog.Translate("fr", "cart.items", map[string]interface{}{"count": 5}); got != "5 articles" {
+		t.Errorf("Expected 5 articles, got %s", got)
+	}
+}
+
+func TestCatalogLoadFS_BrokenFile_NamesFile(t *testing.T) {
+	fsys := fstest.MapFS{"locales/en.json": &fstest.MapFile{Data: []byte("{")}}
+	catalog := i18n.NewCatalog("en")
+
+	err := catalog.LoadFS(fsys, "locales")
+
+	if err == nil || err.Error()[:len("locales/en.json")] != "locales/en.json" {
+		t.Errorf("Expected an error naming the file, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LocaleResolver returns the locales a request asks for, most preferred first. Catalog.Match picks the first one
+// the catalog has.
+type LocaleResolver func(c echo.Context) []string
+
+// Param reads the locale from a route parameter, such as /:lang/about.
+func Param(name string) LocaleResolver {
+	return func(c echo.Context) []string {
+		return nonEmpty(c.Param(name))
+	}
+}
+
+// Query reads the locale from a query parameter, such as ?lang=fr.
+func Query(name string) LocaleResolver {
+	return func(c echo.Context) []string {
+		return nonEmpty(c.QueryParam(name))
+	}
+}
+
+// Cookie reads the locale from a cookie.
+func Cookie(name string) LocaleResolver {
+	return func(c echo.Context) []string {
+		cookie, err := c.Cookie(name)
+		if err != nil {
+			return nil
+		}
+		return nonEmpty(cookie.Value)
+	}
+}
+
+// AcceptLanguage reads the locales from the Accept-Language header, ordered by their quality values.
+func AcceptLanguage(c echo.Context) []string {
+	return ParseAcceptLanguage(c.Request().Header.Get("Accept-Language"))
+}
+
+// Chain tries each resolver in turn, so a route parameter or cookie can override the Accept-Language header.
+//
+//	i18n.Chain(i18n.Param("lang"), i18n.Cookie("lang"), i18n.AcceptLanguage)
+func Chain(resolvers ...LocaleResolver) LocaleResolver {
+	return func(c echo.Context) []string {
+		locales := make([]string, 0)
+		for _, resolver := range resolvers {
+			locales = append(locales, resolver(c)...)
+		}
+		return locales
+	}
+}
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language header, highest quality first.
+// The wildcard and tags with a quality of 0 are left out.
+func ParseAcceptLanguage(header string) []string {
+	type tag struct {
+		name    string
+		quality float64
+	}
+	tags := make([]tag, 0)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		name := strings.TrimSpace(fields[0])
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if name == "" || name == "*" || quality <= 0 {
+			continue
+		}
+		tags = append(tags, tag{name: name, quality: quality})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.name
+	}
+	return names
+}
+
+func nonEmpty(locale string) []string {
+	if locale == "" {
+		return nil
+	}
+	return []string{locale}
+}
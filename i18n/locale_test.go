@@ -0,0 +1,36 @@
+package i18n_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/BlindGarret/echorend/i18n"
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseAcceptLanguage_Header_OrdersByQuality(t *testing.T) {
+	got := i18n.ParseAcceptLanguage("de;q=0.7, fr-CH, fr;q=0.9, *;q=0.5, en;q=0")
+
+	if !reflect.DeepEqual(got, []string{"fr-CH", "fr", "de"}) {
+		t.Errorf("Unexpected order %v", got)
+	}
+}
+
+func TestChain_Resolvers_ReturnsCandidatesInOrder(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("GET", "/?lang=pt", nil)
+	req.Header.Set("Accept-Language", "de")
+	req.AddCookie(&http.Cookie{Name: "lang", Value: "fr"})
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("lang")
+	c.SetParamValues("es")
+	resolver := i18n.Chain(i18n.Param("lang"), i18n.Cookie("lang"), i18n.Query("lang"), i18n.Cookie("missing"), i18n.AcceptLanguage)
+
+	got := resolver(c)
+
+	if !reflect.DeepEqual(got, []string{"es", "fr", "pt", "de"}) {
+		t.Errorf("Unexpected candidates %v", got)
+	}
+}
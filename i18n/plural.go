@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+// PluralRule returns the plural category, such as "one", "few" or "other", a count falls in.
+type PluralRule func(n float64) string
+
+var (
+	pluralMutex sync.RWMutex
+	pluralRules = map[string]PluralRule{
+		"fr": zeroOrOne,
+		"pt": zeroOrOne,
+		"ja": alwaysOther,
+		"ko": alwaysOther,
+		"zh": alwaysOther,
+		"vi": alwaysOther,
+		"th": alwaysOther,
+		"id": alwaysOther,
+		"ru": eastSlavic,
+		"uk": eastSlavic,
+		"be": eastSlavic,
+		"pl": polish,
+		"cs": czechSlovak,
+		"sk": czechSlovak,
+		"ro": romanian,
+	}
+)
+
+// RegisterPluralRule sets the plural rule for a language, or a full locale such as "pt-BR".
+// Languages without a rule use English rules: "one" for exactly 1 and "other" otherwise.
+func RegisterPluralRule(language string, rule PluralRule) {
+	pluralMutex.Lock()
+	defer pluralMutex.Unlock()
+	pluralRules[strings.ToLower(language)] = rule
+}
+
+// PluralCategory returns the plural category of a count in a locale.
+func PluralCategory(locale string, n float64) string {
+	pluralMutex.RLock()
+	rule, ok := pluralRules[strings.ToLower(locale)]
+	if !ok {
+		rule, ok = pluralRules[strings.ToLower(baseLanguage(locale))]
+	}
+	pluralMutex.RUnlock()
+	if !ok {
+		rule = oneOrOther
+	}
+	return rule(n)
+}
+
+func oneOrOther(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func zeroOrOne(n float64) string {
+	if n >= 0 && n < 2 {
+		return "one"
+	}
+	return "other"
+}
+
+func alwaysOther(float64) string {
+	return "other"
+}
+
+func eastSlavic(n float64) string {
+	if n != math.Trunc(n) {
+		return "other"
+	}
+	mod10, mod100 := int64(n)%10, int64(n)%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func polish(n float64) string {
+	if n != math.Trunc(n) {
+		return "other"
+	}
+	mod10, mod100 := int64(n)%10, int64(n)%100
+	switch {
+	case n == 1:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func czechSlovak(n float64) string {
+	switch {
+	case n != math.Trunc(n):
+		return "many"
+	case n == 1:
+		return "one"
+	case n >= 2 && n <= 4:
+		return "few"
+	}
+	return "other"
+}
+
+func romanian(n float64) string {
+	mod100 := int64(n) % 100
+	switch {
+	case n == 1:
+		return "one"
+	case n != math.Trunc(n) || n == 0 || (mod100 >= 2 && mod100 <= 19):
+		return "few"
+	}
+	return "other"
+}
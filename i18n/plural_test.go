@@ -0,0 +1,46 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/BlindGarret/echorend/i18n"
+)
+
+func TestPluralCategory_Locales_FollowLanguageRules(t *testing.T) {
+	cases := []struct {
+		locale string
+		n      float64
+		want   string
+	}{
+		{"en", 1, "one"},
+		{"en-GB", 0, "other"},
+		{"fr", 1.5, "one"},
+		{"ja", 1, "other"},
+		{"ru", 21, "one"},
+		{"ru", 3, "few"},
+		{"ru", 12, "many"},
+		{"ru", 1.5, "other"},
+		{"pl", 22, "few"},
+		{"pl", 21, "many"},
+		{"cs", 3, "few"},
+		{"sk", 5, "other"},
+		{"ro", 0, "few"},
+		{"ro", 119, "few"},
+		{"ro", 20, "other"},
+	}
+	for _, c := range cases {
+		if got := i18n.PluralCategory(c.locale, c.n); got != c.want {
+			t.Errorf("%s %v: expected %s, got %s", c.locale, c.n, c.want, got)
+		}
+	}
+}
+
+func TestRegisterPluralRule_NewLanguage_IsUsed(t *testing.T) {
+	i18n.RegisterPluralRule("x-test", func(n float64) string { return "two" })
+
+	got := i18n.PluralCategory("x-test", 5)
+
+	if got != "two" {
+		t.Errorf("Expected registered rule, got %s", got)
+	}
+}
@@ -0,0 +1,201 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// poCategories maps the msgstr[n] forms of a gettext entry to plural categories, by the number of forms, for files
+// without a Plural-Forms header. Files with more forms need the header, as the forms differ between languages.
+var poCategories = map[int][]string{
+	1: {"other"},
+	2: {"one", "other"},
+}
+
+// poPluralForms maps the plural expressions of Plural-Forms headers to the plural category of each msgstr[n] form.
+// Expressions are compared without spaces or parentheses, so the usual variations of each are recognised.
+var poPluralForms = map[string][]string{
+	"0":    {"other"},
+	"n!=1": {"one", "other"},
+	"n>1":  {"one", "other"},
+	// ru, uk, be
+	"n%10==1&&n%100!=11?0:n%10>=2&&n%10<=4&&n%100<10||n%100>=20?1:2": {"one", "few", "many"},
+	"n%10==1&&n%100!=11?0:n%10>=2&&n%10<=4&&n%100<12||n%100>14?1:2":  {"one", "few", "many"},
+	// pl
+	"n==1?0:n%10>=2&&n%10<=4&&n%100<10||n%100>=20?1:2": {"one", "few", "many"},
+	"n==1?0:n%10>=2&&n%10<=4&&n%100<12||n%100>14?1:2":  {"one", "few", "many"},
+	// cs, sk
+	"n==1?0:n>=2&&n<=4?1:2": {"one", "few", "other"},
+	// ro
+	"n==1?0:n==0||n%100>0&&n%100<20?1:2": {"one", "few", "other"},
+}
+
+type poEntry struct {
+	id      string
+	plural  bool
+	strs    map[int]string
+	lastKey string
+}
+
+// LoadPO adds the messages of a locale from a gettext .po file, keyed by msgid. Entries without a translation,
+// fuzzy entries and the header are skipped, and msgctxt is ignored. Plural forms are labelled by the header's
+// Plural-Forms, and files with plural entries and a Plural-Forms expression it doesn't recognise are rejected.
+func (c *Catalog) LoadPO(locale string, data []byte) error {
+	entry := &poEntry{strs: make(map[int]string)}
+	fuzzy := false
+	var plurals []string
+	var pluralsErr error
+	flush := func() error {
+		if entry.id == "" && len(entry.strs) > 0 {
+			plurals, pluralsErr = poHeaderPlurals(entry.strs[0])
+		} else if entry.id != "" && !fuzzy {
+			if err := c.addPOEntry(locale, entry, plurals, pluralsErr); err != nil {
+				return err
+			}
+		}
+		entry = &poEntry{strs: make(map[int]string)}
+		fuzzy = false
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(line, "#,") && strings.Contains(line, "fuzzy"):
+			fuzzy = true
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			text, err := strconv.Unquote(line)
+			if err != nil {
+				return fmt.Errorf("loading %s messages: line %d: %w", locale, lineNo, err)
+			}
+			entry.appendText(text)
+			continue
+		}
+
+		keyword, value := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			keyword, value = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		text, err := strconv.Unquote(value)
+		if err != nil {
+			return fmt.Errorf("loading %s messages: line %d: %w", locale, lineNo, err)
+		}
+		switch {
+		case keyword == "msgctxt":
+			if entry.id != "" || len(entry.strs) > 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			entry.lastKey = keyword
+		case keyword == "msgid":
+			if entry.id != "" || len(entry.strs) > 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			entry.id = text
+			entry.lastKey = keyword
+		case keyword == "msgid_plural":
+			entry.plural = true
+			entry.lastKey = keyword
+		case keyword == "msgstr":
+			entry.strs[0] = text
+			entry.lastKey = "0"
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			n, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil {
+				return fmt.Errorf("loading %s messages: line %d: bad plural index %s", locale, lineNo, keyword)
+			}
+			entry.strs[n] = text
+			entry.lastKey = strconv.Itoa(n)
+		default:
+			return fmt.Errorf("loading %s messages: line %d: unknown keyword %s", locale, lineNo, keyword)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// appendText adds a continuation string to whichever part of the entry was last started.
+func (e *poEntry) appendText(text string) {
+	switch e.lastKey {
+	case "msgid":
+		e.id += text
+	case "msgid_plural", "msgctxt", "":
+	default:
+		n, _ := strconv.Atoi(e.lastKey)
+		e.strs[n] += text
+	}
+}
+
+func (c *Catalog) addPOEntry(locale string, entry *poEntry, plurals []string, pluralsErr error) error {
+	if !entry.plural {
+		if entry.strs[0] != "" {
+			c.Add(locale, entry.id, Text(entry.strs[0]))
+		}
+		return nil
+	}
+	if pluralsErr != nil {
+		return fmt.Errorf("loading %s messages: %w", locale, pluralsErr)
+	}
+	categories := plurals
+	if categories == nil {
+		var ok bool
+		if categories, ok = poCategories[len(entry.strs)]; !ok {
+			return fmt.Errorf("loading %s messages: %s has %d plural forms, which need a Plural-Forms header", locale, entry.id, len(entry.strs))
+		}
+	}
+	if len(entry.strs) != len(categories) {
+		return fmt.Errorf("loading %s messages: %s has %d plural forms, Plural-Forms has %d", locale, entry.id, len(entry.strs), len(categories))
+	}
+	forms := make(map[string]string)
+	for i, category := range categories {
+		if entry.strs[i] == "" {
+			return nil
+		}
+		forms[category] = entry.strs[i]
+	}
+	if _, ok := forms["other"]; !ok {
+		forms["other"] = forms[categories[len(categories)-1]]
+	}
+	c.Add(locale, entry.id, Message{Forms: forms})
+	return nil
+}
+
+// poHeaderPlurals returns the plural categories of a header's Plural-Forms, or nil when it has none.
+func poHeaderPlurals(header string) ([]string, error) {
+	for _, line := range strings.Split(header, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "Plural-Forms") {
+			continue
+		}
+		expression := ""
+		for _, part := range strings.Split(value, ";") {
+			if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok && strings.TrimSpace(key) == "plural" {
+				expression = value
+			}
+		}
+		expression = strings.NewReplacer(" ", "", "\t", "", "(", "", ")", "").Replace(expression)
+		categories, ok := poPluralForms[expression]
+		if !ok {
+			return nil, fmt.Errorf("unsupported Plural-Forms %q", strings.TrimSpace(value))
+		}
+		return categories, nil
+	}
+	return nil, nil
+}
@@ -1,11 +1,31 @@
 package echorend
 
-import "github.com/labstack/echo/v4"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
 
 // RawTemplateData is a data struct for passing around template data
 type RawTemplateData struct {
 	TemplateName string
 	TemplateData string
+	// Source is where the template was read from, such as its file path.
+	Source string
+	// ModTime is when the template source was last modified, if the gatherer knows.
+	ModTime time.Time
+	// Hash is a hex encoded SHA-256 of TemplateData, see ContentHash.
+	Hash string
+	// Gatherer describes the gatherer the template came from, such as "glob:templates/views".
+	Gatherer string
+}
+
+// ContentHash returns the hash gatherers should use for RawTemplateData.Hash.
+func ContentHash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
 }
 
 // RawTemplateGatherer is the interface for implementing Gatherers for the renderer to use during setup.
@@ -14,6 +34,12 @@ type RawTemplateGatherer interface {
 	Gather() ([]RawTemplateData, error)
 }
 
+// WatchableGatherer is a RawTemplateGatherer backed by directories which can be watched for changes.
+type WatchableGatherer interface {
+	RawTemplateGatherer
+	WatchDirs() []string
+}
+
 // Renderer is the interface for implementing renderers for the echo framework.
 type Renderer interface {
 	echo.Renderer
@@ -0,0 +1,34 @@
+package echorend
+
+import "strings"
+
+// NamespaceSeparator separates a template's namespace from the rest of its name, as in billing:invoice/show.
+const NamespaceSeparator = ":"
+
+// QualifiedName returns the name of a template within a namespace, such as billing:invoice/show.
+// An empty namespace leaves the name unqualified.
+func QualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + NamespaceSeparator + name
+}
+
+// SplitName splits a template name into its namespace and the name within it. Names may be written as
+// billing:invoice/show or @billing/invoice/show. Unqualified names have an empty namespace.
+func SplitName(name string) (namespace string, local string) {
+	if strings.HasPrefix(name, "@") {
+		if i := strings.IndexByte(name, '/'); i > 1 {
+			return name[1:i], name[i+1:]
+		}
+	}
+	if i := strings.Index(name, NamespaceSeparator); i > 0 {
+		return name[:i], name[i+len(NamespaceSeparator):]
+	}
+	return "", name
+}
+
+// CanonicalName returns a template name in the billing:invoice/show form, whichever way it was written.
+func CanonicalName(name string) string {
+	return QualifiedName(SplitName(name))
+}
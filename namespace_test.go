@@ -0,0 +1,43 @@
+package echorend_test
+
+import (
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+)
+
+func TestSplitName_Forms_ReturnsNamespaceAndName(t *testing.T) {
+	cases := []struct {
+		name, namespace, local string
+	}{
+		{"billing:invoice/show", "billing", "invoice/show"},
+		{"@billing/invoice/show", "billing", "invoice/show"},
+		{"invoice/show", "", "invoice/show"},
+		{"@/show", "", "@/show"},
+		{":show", "", ":show"},
+	}
+	for _, c := range cases {
+		namespace, local := echorend.SplitName(c.name)
+		if namespace != c.namespace || local != c.local {
+			t.Errorf("%s: expected %q and %q, got %q and %q", c.name, c.namespace, c.local, namespace, local)
+		}
+	}
+}
+
+func TestCanonicalName_AtForm_ReturnsSeparatorForm(t *testing.T) {
+	if got := echorend.CanonicalName("@billing/invoice/show"); got != "billing:invoice/show" {
+		t.Errorf("Expected billing:invoice/show, got %s", got)
+	}
+	if got := echorend.CanonicalName("index"); got != "index" {
+		t.Errorf("Expected index, got %s", got)
+	}
+}
+
+func TestQualifiedName_EmptyNamespace_LeavesNameAlone(t *testing.T) {
+	if got := echorend.QualifiedName("", "index"); got != "index" {
+		t.Errorf("Expected index, got %s", got)
+	}
+	if got := echorend.QualifiedName("billing", "index"); got != "billing:index" {
+		t.Errorf("Expected billing:index, got %s", got)
+	}
+}
@@ -0,0 +1,48 @@
+package echorend
+
+import (
+	"context"
+	"time"
+)
+
+// Operation is the kind of work an Event describes.
+type Operation string
+
+const (
+	OperationRender Operation = "render"
+	OperationSetup  Operation = "setup"
+	OperationReload Operation = "reload"
+)
+
+// Event describes a single render, setup or reload, after it has finished.
+type Event struct {
+	Operation Operation
+	// Template is the name of the template rendered. It is empty for setups and reloads.
+	Template string
+	Start    time.Time
+	Duration time.Duration
+	// Size is the number of bytes a render wrote, or the number of templates a setup or reload built.
+	Size int
+	Err  error
+	// Context is the request context of a render, or context.Background outside of a request.
+	Context context.Context
+}
+
+// Observer is told about every render, setup and reload of a renderer, for metrics and tracing.
+// Observe is called synchronously and from many goroutines at once, so it must be fast and safe for concurrent use.
+type Observer interface {
+	Observe(event Event)
+}
+
+// Observers combines several observers into one, which tells each of them about every event in order.
+func Observers(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) Observe(event Event) {
+	for _, observer := range m {
+		observer.Observe(event)
+	}
+}
@@ -0,0 +1,30 @@
+package echorend_test
+
+import (
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+)
+
+type recordingObserver struct {
+	name   string
+	record *[]string
+}
+
+func (o recordingObserver) Observe(event echorend.Event) {
+	*o.record = append(*o.record, o.name+" "+event.Template)
+}
+
+func TestObservers_Observe_TellsEveryObserverInOrder(t *testing.T) {
+	record := make([]string, 0)
+	observer := echorend.Observers(
+		recordingObserver{name: "first", record: &record},
+		recordingObserver{name: "second", record: &record},
+	)
+
+	observer.Observe(echorend.Event{Operation: echorend.OperationRender, Template: "index"})
+
+	if len(record) != 2 || record[0] != "first index" || record[1] != "second index" {
+		t.Errorf("Expected both observers told in order, got %v", record)
+	}
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/BlindGarret/echorend"
+)
+
+// MemoryObserver records every event it is told about, so tests can check what a renderer did without any
+// external service.
+type MemoryObserver struct {
+	mutex  sync.Mutex
+	events []echorend.Event
+}
+
+func NewMemoryObserver() *MemoryObserver {
+	return &MemoryObserver{
+		events: make([]echorend.Event, 0),
+	}
+}
+
+// Observe records the event.
+func (o *MemoryObserver) Observe(event echorend.Event) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.events = append(o.events, event)
+}
+
+// Events returns every event recorded so far, in the order they were observed.
+func (o *MemoryObserver) Events() []echorend.Event {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	events := make([]echorend.Event, len(o.events))
+	copy(events, o.events)
+	return events
+}
+
+// Operations returns the recorded events of a single operation, such as every render.
+func (o *MemoryObserver) Operations(operation echorend.Operation) []echorend.Event {
+	events := make([]echorend.Event, 0)
+	for _, event := range o.Events() {
+		if event.Operation == operation {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Reset forgets every recorded event.
+func (o *MemoryObserver) Reset() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.events = make([]echorend.Event, 0)
+}
@@ -0,0 +1,61 @@
+package memory_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/observers/memory"
+)
+
+func TestMemoryObserver_Interface_CompliesWithObserver(t *testing.T) {
+	_, ok := interface{}(memory.NewMemoryObserver()).(echorend.Observer)
+	if !ok {
+		t.Fatalf("MemoryObserver does not comply with Observer interface")
+	}
+}
+
+func TestMemoryObserver_Observe_RecordsEventsInOrder(t *testing.T) {
+	observer := memory.NewMemoryObserver()
+
+	observer.Observe(echorend.Event{Operation: echorend.OperationSetup})
+	observer.Observe(echorend.Event{Operation: echorend.OperationRender, Template: "index"})
+	observer.Observe(echorend.Event{Operation: echorend.OperationRender, Template: "about"})
+
+	events := observer.Events()
+	if len(events) != 3 || events[0].Operation != echorend.OperationSetup {
+		t.Fatalf("Expected 3 events starting with setup, got %v", events)
+	}
+	renders := observer.Operations(echorend.OperationRender)
+	if len(renders) != 2 || renders[0].Template != "index" || renders[1].Template != "about" {
+		t.Errorf("Expected the renders of index and about, got %v", renders)
+	}
+}
+
+func TestMemoryObserver_Reset_ForgetsEvents(t *testing.T) {
+	observer := memory.NewMemoryObserver()
+	observer.Observe(echorend.Event{Operation: echorend.OperationSetup})
+
+	observer.Reset()
+
+	if events := observer.Events(); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+func TestMemoryObserver_ConcurrentObserve_RecordsEveryEvent(t *testing.T) {
+	observer := memory.NewMemoryObserver()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			observer.Observe(echorend.Event{Operation: echorend.OperationRender})
+		}()
+	}
+	wg.Wait()
+
+	if events := observer.Events(); len(events) != 50 {
+		t.Errorf("Expected 50 events, got %d", len(events))
+	}
+}
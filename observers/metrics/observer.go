@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"github.com/BlindGarret/echorend"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsObserverConfig is a configuration struct for creating a MetricsObserver.
+type MetricsObserverConfig struct {
+	// Namespace prefixes every metric name, defaulting to echorend.
+	Namespace string
+	// Buckets are the duration histogram's buckets in seconds, defaulting to prometheus.DefBuckets.
+	Buckets []float64
+}
+
+// MetricsObserver collects Prometheus metrics of every render, setup and reload. It is a prometheus.Collector,
+// so register it with a registry to export them:
+//   - echorend_duration_seconds, a histogram by operation and template
+//   - echorend_errors_total, a counter by operation and template
+//   - echorend_rendered_bytes_total, a counter by template
+//
+// Setups and reloads have an empty template label.
+type MetricsObserver struct {
+	durations *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+	bytes     *prometheus.CounterVec
+}
+
+func NewMetricsObserver() *MetricsObserver {
+	return NewMetricsObserverWithConfig(MetricsObserverConfig{})
+}
+
+func NewMetricsObserverWithConfig(config MetricsObserverConfig) *MetricsObserver {
+	config = defaultMetricsObserverConfig(config)
+	return &MetricsObserver{
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "duration_seconds",
+			Help:      "Time taken by template renders, setups and reloads.",
+			Buckets:   config.Buckets,
+		}, []string{"operation", "template"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "errors_total",
+			Help:      "Failed template renders, setups and reloads.",
+		}, []string{"operation", "template"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "rendered_bytes_total",
+			Help:      "Bytes written by template renders.",
+		}, []string{"template"}),
+	}
+}
+
+// Observe records the event's duration, and its error or output size.
+func (o *MetricsObserver) Observe(event echorend.Event) {
+	operation := string(event.Operation)
+	o.durations.WithLabelValues(operation, event.Template).Observe(event.Duration.Seconds())
+	if event.Err != nil {
+		o.errors.WithLabelValues(operation, event.Template).Inc()
+	}
+	if event.Operation == echorend.OperationRender {
+		o.bytes.WithLabelValues(event.Template).Add(float64(event.Size))
+	}
+}
+
+// Describe sends the descriptors of every metric, implementing prometheus.Collector.
+func (o *MetricsObserver) Describe(ch chan<- *prometheus.Desc) {
+	o.durations.Describe(ch)
+	o.errors.Describe(ch)
+	o.bytes.Describe(ch)
+}
+
+// Collect sends every metric, implementing prometheus.Collector.
+func (o *MetricsObserver) Collect(ch chan<- prometheus.Metric) {
+	o.durations.Collect(ch)
+	o.errors.Collect(ch)
+	o.bytes.Collect(ch)
+}
+
+func defaultMetricsObserverConfig(config MetricsObserverConfig) MetricsObserverConfig {
+	if config.Namespace == "" {
+		config.Namespace = "echorend"
+	}
+	if config.Buckets == nil {
+		config.Buckets = prometheus.DefBuckets
+	}
+	return config
+}
@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/observers/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsObserver_Interface_CompliesWithObserverAndCollector(t *testing.T) {
+	observer := metrics.NewMetricsObserver()
+	if _, ok := interface{}(observer).(echorend.Observer); !ok {
+		t.Fatalf("MetricsObserver does not comply with Observer interface")
+	}
+	if _, ok := interface{}(observer).(prometheus.Collector); !ok {
+		t.Fatalf("MetricsObserver does not comply with prometheus.Collector interface")
+	}
+}
+
+func TestMetricsObserver_Observe_CollectsDurationsErrorsAndBytes(t *testing.T) {
+	observer := metrics.NewMetricsObserver()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(observer)
+
+	observer.Observe(echorend.Event{Operation: echorend.OperationRender, Template: "index", Duration: time.Millisecond, Size: 100})
+	observer.Observe(echorend.Event{Operation: echorend.OperationRender, Template: "index", Duration: time.Millisecond, Size: 50})
+	observer.Observe(echorend.Event{Operation: echorend.OperationRender, Template: "about", Duration: time.Millisecond, Err: errors.New("failed")})
+	observer.Observe(echorend.Event{Operation: echorend.OperationSetup, Duration: time.Second, Size: 3})
+
+	expected := `
+# HELP echorend_errors_total Failed template renders, setups and reloads.
+# TYPE echorend_errors_total counter
+echorend_errors_total{operation="render",template="about"} 1
+# HELP echorend_rendered_bytes_total Bytes written by template renders.
+# TYPE echorend_rendered_bytes_total counter
+echorend_rendered_bytes_total{template="about"} 0
+echorend_rendered_bytes_total{template="index"} 150
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "echorend_errors_total", "echorend_rendered_bytes_total"); err != nil {
+		t.Error(err)
+	}
+	if count := testutil.CollectAndCount(observer, "echorend_duration_seconds"); count != 3 {
+		t.Errorf("Expected durations of 3 operation and template pairs, got %d", count)
+	}
+}
+
+func TestMetricsObserver_Namespace_PrefixesMetrics(t *testing.T) {
+	observer := metrics.NewMetricsObserverWithConfig(metrics.MetricsObserverConfig{Namespace: "views", Buckets: []float64{0.1, 1}})
+
+	observer.Observe(echorend.Event{Operation: echorend.OperationRender, Template: "index"})
+
+	if count := testutil.CollectAndCount(observer, "views_duration_seconds"); count != 1 {
+		t.Errorf("Expected 1 duration series, got %d", count)
+	}
+}
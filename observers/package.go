@@ -0,0 +1,2 @@
+// Package observers contains implementations of the Observer interface, for watching renderers in tests and production.
+package observers
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/BlindGarret/echorend"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer spans are emitted by.
+const InstrumentationName = "github.com/BlindGarret/echorend"
+
+// Attribute keys of the emitted spans.
+const (
+	TemplateKey = attribute.Key("echorend.template")
+	SizeKey     = attribute.Key("echorend.size")
+)
+
+// TracingObserverConfig is a configuration struct for creating a TracingObserver.
+type TracingObserverConfig struct {
+	// TracerProvider creates the tracer spans are emitted by, defaulting to the global otel.GetTracerProvider.
+	TracerProvider trace.TracerProvider
+}
+
+// TracingObserver emits an OpenTelemetry span for every render, setup and reload, named echorend.render,
+// echorend.setup or echorend.reload. Render spans are children of the span in the request's context.
+type TracingObserver struct {
+	tracer trace.Tracer
+}
+
+func NewTracingObserver() *TracingObserver {
+	return NewTracingObserverWithConfig(TracingObserverConfig{})
+}
+
+func NewTracingObserverWithConfig(config TracingObserverConfig) *TracingObserver {
+	config = defaultTracingObserverConfig(config)
+	return &TracingObserver{
+		tracer: config.TracerProvider.Tracer(InstrumentationName),
+	}
+}
+
+// Observe emits a span covering the event, marked as an error if it failed.
+func (o *TracingObserver) Observe(event echorend.Event) {
+	ctx := event.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attributes := []attribute.KeyValue{SizeKey.Int(event.Size)}
+	if event.Template != "" {
+		attributes = append(attributes, TemplateKey.String(event.Template))
+	}
+
+	_, span := o.tracer.Start(ctx, "echorend."+string(event.Operation),
+		trace.WithTimestamp(event.Start),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attributes...),
+	)
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+	span.End(trace.WithTimestamp(event.Start.Add(event.Duration)))
+}
+
+func defaultTracingObserverConfig(config TracingObserverConfig) TracingObserverConfig {
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	return config
+}
@@ -0,0 +1,83 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/observers/tracing"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecordedObserver() (*tracing.TracingObserver, *tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	observer := tracing.NewTracingObserverWithConfig(tracing.TracingObserverConfig{TracerProvider: provider})
+	return observer, recorder, provider
+}
+
+func TestTracingObserver_Interface_CompliesWithObserver(t *testing.T) {
+	_, ok := interface{}(tracing.NewTracingObserver()).(echorend.Observer)
+	if !ok {
+		t.Fatalf("TracingObserver does not comply with Observer interface")
+	}
+}
+
+func TestTracingObserver_Render_EmitsChildSpanCoveringEvent(t *testing.T) {
+	observer, recorder, provider := newRecordedObserver()
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	observer.Observe(echorend.Event{
+		Operation: echorend.OperationRender,
+		Template:  "index",
+		Start:     start,
+		Duration:  25 * time.Millisecond,
+		Size:      512,
+		Context:   ctx,
+	})
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "echorend.render" {
+		t.Errorf("Expected span echorend.render, got %s", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected the render span to be a child of the request span")
+	}
+	if !span.StartTime().Equal(start) || !span.EndTime().Equal(start.Add(25*time.Millisecond)) {
+		t.Errorf("Expected the span to cover the event, got %v to %v", span.StartTime(), span.EndTime())
+	}
+	attributes := make(map[string]string)
+	for _, attribute := range span.Attributes() {
+		attributes[string(attribute.Key)] = attribute.Value.Emit()
+	}
+	if attributes["echorend.template"] != "index" || attributes["echorend.size"] != "512" {
+		t.Errorf("Unexpected attributes %v", attributes)
+	}
+}
+
+func TestTracingObserver_Error_MarksSpanFailed(t *testing.T) {
+	observer, recorder, _ := newRecordedObserver()
+
+	observer.Observe(echorend.Event{Operation: echorend.OperationReload, Start: time.Now(), Err: errors.New("parse error")})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name() != "echorend.reload" || spans[0].Status().Code != codes.Error {
+		t.Errorf("Expected a failed echorend.reload span, got %s %v", spans[0].Name(), spans[0].Status())
+	}
+	if len(spans[0].Events()) != 1 {
+		t.Errorf("Expected the error recorded on the span, got %v", spans[0].Events())
+	}
+}
@@ -0,0 +1,55 @@
+package gotemplate
+
+import (
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// templateSet hides the differences between html/template and text/template associated template sets.
+type templateSet interface {
+	parse(name string, text string) error
+	clone() (templateSet, error)
+	lookup(name string) executor
+}
+
+type htmlTemplateSet struct {
+	tmpl *htmltemplate.Template
+}
+
+func (s *htmlTemplateSet) parse(name string, text string) error {
+	_, err := s.tmpl.New(name).Parse(text)
+	return err
+}
+
+func (s *htmlTemplateSet) clone() (templateSet, error) {
+	tmpl, err := s.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &htmlTemplateSet{tmpl}, nil
+}
+
+func (s *htmlTemplateSet) lookup(name string) executor {
+	return s.tmpl.Lookup(name)
+}
+
+type textTemplateSet struct {
+	tmpl *texttemplate.Template
+}
+
+func (s *textTemplateSet) parse(name string, text string) error {
+	_, err := s.tmpl.New(name).Parse(text)
+	return err
+}
+
+func (s *textTemplateSet) clone() (templateSet, error) {
+	tmpl, err := s.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return &textTemplateSet{tmpl}, nil
+}
+
+func (s *textTemplateSet) lookup(name string) executor {
+	return s.tmpl.Lookup(name)
+}
@@ -0,0 +1,199 @@
+package gotemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/labstack/echo/v4"
+)
+
+// GoTemplateRendererConfig is a configuration struct for creating a GoTemplateRenderer.
+type GoTemplateRendererConfig struct {
+	ViewGatherer    echorend.RawTemplateGatherer
+	PartialGatherer echorend.RawTemplateGatherer
+	// Funcs are made available to every template, as with template.Funcs.
+	Funcs map[string]interface{}
+	// TextMode uses text/template rather than html/template, for output which is not HTML and must not be escaped.
+	TextMode bool
+	// CollisionPolicy decides what happens when views, or partials, share a name. A view and partial sharing a name
+	// can't live in the same template set, so that is always reported as a collision.
+	CollisionPolicy echorend.CollisionPolicy
+}
+
+// GoTemplateRenderer is a renderer that uses Go's html/template (or text/template) library to render templates.
+// Partials are added to every view's template set as named templates, for use with {{template "name" .}}.
+type GoTemplateRenderer struct {
+	config GoTemplateRendererConfig
+
+	mutex     sync.RWMutex
+	templates map[string]executor
+}
+
+// executor is the part of html/template and text/template templates the renderer needs.
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+func NewGoTemplateRenderer(
+	viewGatherer echorend.RawTemplateGatherer,
+	partialsGatherer echorend.RawTemplateGatherer,
+) *GoTemplateRenderer {
+	return NewGoTemplateRendererWithConfig(GoTemplateRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialsGatherer,
+	})
+}
+
+func NewGoTemplateRendererWithConfig(config GoTemplateRendererConfig) *GoTemplateRenderer {
+	return &GoTemplateRenderer{
+		config:    config,
+		templates: make(map[string]executor),
+	}
+}
+
+// Setup initializes the renderer by gathering templates from the view and partial gatherers and parsing them for render calls.
+func (r *GoTemplateRenderer) Setup() error {
+	views, err := gather(r.config.ViewGatherer)
+	if err != nil {
+		return err
+	}
+	partials, err := gather(r.config.PartialGatherer)
+	if err != nil {
+		return err
+	}
+
+	views, partials, err = r.resolveCollisions(views, partials)
+	if err != nil {
+		return err
+	}
+
+	base := r.newTemplateSet()
+	partialNames := make(map[string]bool)
+	for _, partial := range partials {
+		if err := base.parse(partial.TemplateName, partial.TemplateData); err != nil {
+			return err
+		}
+		partialNames[partial.TemplateName] = true
+	}
+
+	// Each view is parsed into its own clone of the partial set, so views can't see or clobber each other's
+	// {{define}}s. Clones must all be taken before anything executes, which html/template forbids afterwards.
+	templates := make(map[string]executor)
+	for _, view := range views {
+		set, err := base.clone()
+		if err != nil {
+			return err
+		}
+		if err := set.parse(view.TemplateName, view.TemplateData); err != nil {
+			return err
+		}
+		templates[view.TemplateName] = set.lookup(view.TemplateName)
+	}
+	for name := range partialNames {
+		templates[name] = base.lookup(name)
+	}
+
+	r.mutex.Lock()
+	r.templates = templates
+	r.mutex.Unlock()
+	return nil
+}
+
+// MustSetup initializes the renderer by gathering templates from the view and partial gatherers
+// and parsing them for render calls. If an error occurs, it panics.
+func (r *GoTemplateRenderer) MustSetup() {
+	if err := r.Setup(); err != nil {
+		panic(err)
+	}
+}
+
+// Render renders a template with the given name and data to the IO writer.
+// this function is designed to slot directly into echo as a renderer
+func (r *GoTemplateRenderer) Render(w io.Writer, name string, data interface{}, _ echo.Context) error {
+	r.mutex.RLock()
+	tmpl, ok := r.templates[name]
+	r.mutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("template %s not found", name)
+	}
+
+	return tmpl.Execute(w, data)
+}
+
+// CheckRenders is a convience tool for rendering all templates with no data
+// to ensure they aren't referencing non-existant partials.
+func (r *GoTemplateRenderer) CheckRenders() []error {
+	r.mutex.RLock()
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	r.mutex.RUnlock()
+
+	errs := make([]error, 0)
+	for _, name := range names {
+		buf := new(bytes.Buffer)
+		if err := r.Render(buf, name, nil, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// resolveCollisions applies the collision policy within the views and within the partials, and reports any
+// view sharing a name with a partial. Every collision is reported together.
+func (r *GoTemplateRenderer) resolveCollisions(
+	views []echorend.RawTemplateData,
+	partials []echorend.RawTemplateData,
+) ([]echorend.RawTemplateData, []echorend.RawTemplateData, error) {
+	collisions := make([]echorend.Collision, 0)
+	var collisionErr *echorend.CollisionError
+
+	views, err := echorend.ResolveCollisions(views, r.config.CollisionPolicy)
+	if errors.As(err, &collisionErr) {
+		collisions = append(collisions, collisionErr.Collisions...)
+	}
+	partials, err = echorend.ResolveCollisions(partials, r.config.CollisionPolicy)
+	if errors.As(err, &collisionErr) {
+		collisions = append(collisions, collisionErr.Collisions...)
+	}
+
+	viewSources := make(map[string]string)
+	for _, view := range views {
+		viewSources[view.TemplateName] = view.Source
+	}
+	for _, partial := range partials {
+		if source, exists := viewSources[partial.TemplateName]; exists {
+			collisions = append(collisions, echorend.Collision{
+				TemplateName: partial.TemplateName,
+				Sources:      []string{source, partial.Source},
+			})
+		}
+	}
+
+	if len(collisions) > 0 {
+		return nil, nil, &echorend.CollisionError{Collisions: collisions}
+	}
+	return views, partials, nil
+}
+
+func (r *GoTemplateRenderer) newTemplateSet() templateSet {
+	if r.config.TextMode {
+		return &textTemplateSet{texttemplate.New("").Funcs(r.config.Funcs)}
+	}
+	return &htmlTemplateSet{htmltemplate.New("").Funcs(r.config.Funcs)}
+}
+
+func gather(gatherer echorend.RawTemplateGatherer) ([]echorend.RawTemplateData, error) {
+	if gatherer == nil {
+		return nil, nil
+	}
+	return gatherer.Gather()
+}
@@ -0,0 +1,340 @@
+package gotemplate_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/gotemplate"
+)
+
+func renderToString(name string, data interface{}, renderer *gotemplate.GoTemplateRenderer) (string, error) {
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, name, data, nil)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func TestGoTemplateRenderer_Interface_CompliesWithRenderer(t *testing.T) {
+	renderer := gotemplate.NewGoTemplateRenderer(nil, nil)
+	_, ok := interface{}(renderer).(echorend.Renderer)
+	if !ok {
+		t.Errorf("GoTemplateRenderer does not comply with the Renderer interface")
+	}
+}
+
+func TestGoTemplateRendererSetup_CalledWithValidTemplates_NoErr(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view1",
+		TemplateData: "<HTML></HTML>",
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-partial1",
+		TemplateData: "<h1>test</h1>",
+	})
+
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, partialGatherer)
+	err := renderer.Setup()
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestGoTemplateRendererSetup_ViewGathererErrors_ReturnsError(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	expectedErr := errors.New("test error")
+	viewGatherer.SetError(expectedErr)
+
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, NewMockTemplateGatherer())
+	err := renderer.Setup()
+
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("Expected error %v, got %v", expectedErr, err)
+	}
+}
+
+func TestGoTemplateRendererSetup_PartialGathererErrors_ReturnsError(t *testing.T) {
+	partialGatherer := NewMockTemplateGatherer()
+	expectedErr := errors.New("test error")
+	partialGatherer.SetError(expectedErr)
+
+	renderer := gotemplate.NewGoTemplateRenderer(NewMockTemplateGatherer(), partialGatherer)
+	err := renderer.Setup()
+
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("Expected error %v, got %v", expectedErr, err)
+	}
+}
+
+func TestGoTemplateRendererSetup_BadTemplates_ReturnsError(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view2",
+		TemplateData: "<HTML>{{.Herp}</HTML>",
+	})
+
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, NewMockTemplateGatherer())
+	err := renderer.Setup()
+
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestGoTemplateRendererSetup_PartialViewNameCollision_ReturnsError(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "same-partial-and-view-name",
+		TemplateData: "<HTML></HTML>",
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "same-partial-and-view-name",
+		TemplateData: "<h1>test</h1>",
+	})
+
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, partialGatherer)
+	err := renderer.Setup()
+
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestGoTemplateRendererMustSetup_ViewGathererErrors_Panics(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.SetError(errors.New("test error"))
+
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, nil)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected panic, got nil")
+		}
+	}()
+	renderer.MustSetup()
+}
+
+func TestGoTemplateRendererRender_TemplateNotFound_Errors(t *testing.T) {
+	renderer := gotemplate.NewGoTemplateRenderer(NewMockTemplateGatherer(), NewMockTemplateGatherer())
+	renderer.MustSetup()
+
+	_, err := renderToString("test-view3", nil, renderer)
+
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestGoTemplateRendererRender_ViewUsesPartial_RendersPartialAsNamedTemplate(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view4",
+		TemplateData: `<HTML>{{template "test-partial4" .}}</HTML>`,
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-partial4",
+		TemplateData: "<h1>{{.Title}}</h1>",
+	})
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, partialGatherer)
+	renderer.MustSetup()
+
+	out, err := renderToString("test-view4", map[string]string{"Title": "Hi"}, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<HTML><h1>Hi</h1></HTML>" {
+		t.Errorf("Unexpected render output %s", out)
+	}
+}
+
+func TestGoTemplateRendererRender_PartialNameToBeRendered_PartialsCanRenderAsIfViews(t *testing.T) {
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "partial-to-render-as-view",
+		TemplateData: "<h1>test</h1>",
+	})
+	renderer := gotemplate.NewGoTemplateRenderer(NewMockTemplateGatherer(), partialGatherer)
+	renderer.MustSetup()
+
+	out, err := renderToString("partial-to-render-as-view", nil, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<h1>test</h1>" {
+		t.Errorf("Unexpected render output %s", out)
+	}
+}
+
+func TestGoTemplateRendererRender_ViewsDefineSameBlock_DoNotClobberEachOther(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view5",
+		TemplateData: `{{define "title"}}five{{end}}{{template "title"}}`,
+	})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view6",
+		TemplateData: `{{define "title"}}six{{end}}{{template "title"}}`,
+	})
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, nil)
+	renderer.MustSetup()
+
+	five, _ := renderToString("test-view5", nil, renderer)
+	six, _ := renderToString("test-view6", nil, renderer)
+
+	if five != "five" || six != "six" {
+		t.Errorf("Expected five and six, got %s and %s", five, six)
+	}
+}
+
+func TestGoTemplateRendererRender_HTMLMode_EscapesData(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view7",
+		TemplateData: "<p>{{.}}</p>",
+	})
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, nil)
+	renderer.MustSetup()
+
+	out, err := renderToString("test-view7", "<script>", renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<p>&lt;script&gt;</p>" {
+		t.Errorf("Expected escaped output, got %s", out)
+	}
+}
+
+func TestGoTemplateRendererRender_TextMode_DoesNotEscapeData(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view8",
+		TemplateData: "Hello {{.}}",
+	})
+	renderer := gotemplate.NewGoTemplateRendererWithConfig(gotemplate.GoTemplateRendererConfig{
+		ViewGatherer: viewGatherer,
+		TextMode:     true,
+	})
+	renderer.MustSetup()
+
+	out, err := renderToString("test-view8", "<b>you</b>", renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "Hello <b>you</b>" {
+		t.Errorf("Expected unescaped output, got %s", out)
+	}
+}
+
+func TestGoTemplateRendererRender_WithFuncs_FuncsAvailableInViewsAndPartials(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view9",
+		TemplateData: `{{upper .}} {{template "test-partial9" .}}`,
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-partial9",
+		TemplateData: "{{upper .}}",
+	})
+	renderer := gotemplate.NewGoTemplateRendererWithConfig(gotemplate.GoTemplateRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		Funcs:           map[string]interface{}{"upper": strings.ToUpper},
+	})
+	renderer.MustSetup()
+
+	out, err := renderToString("test-view9", "hi", renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "HI HI" {
+		t.Errorf("Unexpected render output %s", out)
+	}
+}
+
+func TestGoTemplateCheckRenders_RunWithValidTemplates_ReturnsNoErrors(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view10",
+		TemplateData: `<HTML>{{template "test-partial10"}}</HTML>`,
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-partial10",
+		TemplateData: "<h1>test</h1>",
+	})
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, partialGatherer)
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+func TestGoTemplateCheckRenders_RunWithInvalidTemplates_ReturnsErrors(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "test-view11",
+		TemplateData: `<HTML>{{template "test-partial11"}}</HTML>`,
+	})
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, NewMockTemplateGatherer())
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(errs))
+	}
+}
+
+func TestGoTemplateRendererSetup_DuplicateViews_ReturnsCollisionError(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view", TemplateData: "one", Source: "views/dup-view.tmpl"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view", TemplateData: "two", Source: "views/dup-view.html"})
+
+	renderer := gotemplate.NewGoTemplateRenderer(viewGatherer, nil)
+	err := renderer.Setup()
+
+	var collisionErr *echorend.CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("Expected CollisionError, got %v", err)
+	}
+	if len(collisionErr.Collisions) != 1 || len(collisionErr.Collisions[0].Sources) != 2 {
+		t.Errorf("Expected one collision with two sources, got %v", collisionErr.Collisions)
+	}
+}
+
+func TestGoTemplateRendererSetup_DuplicateViewsWithLastWinsPolicy_RendersLast(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view2", TemplateData: "one"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view2", TemplateData: "two"})
+	renderer := gotemplate.NewGoTemplateRendererWithConfig(gotemplate.GoTemplateRendererConfig{
+		ViewGatherer:    viewGatherer,
+		CollisionPolicy: echorend.CollisionLastWins,
+	})
+	renderer.MustSetup()
+
+	out, err := renderToString("dup-view2", nil, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "two" {
+		t.Errorf("Expected last view, got %s", out)
+	}
+}
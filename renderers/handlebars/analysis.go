@@ -0,0 +1,333 @@
+package handlebars
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/aymerick/raymond/ast"
+	"github.com/aymerick/raymond/parser"
+)
+
+// walker visits every node of a template's AST, calling whichever hooks are set. Unlike rendering, it walks
+// every branch of every block regardless of data, so it sees what a nil data render would skip.
+type walker struct {
+	onPartial    func(node *ast.PartialStatement)
+	onExpression func(node *ast.Expression, block *ast.BlockStatement)
+	onPath       func(node *ast.PathExpression)
+
+	// block is the block statement whose expression is being visited, if any.
+	block *ast.BlockStatement
+}
+
+func (w *walker) walk(program *ast.Program) {
+	program.Accept(w)
+}
+
+func (w *walker) VisitProgram(node *ast.Program) interface{} {
+	for _, statement := range node.Body {
+		statement.Accept(w)
+	}
+	return nil
+}
+
+func (w *walker) VisitMustache(node *ast.MustacheStatement) interface{} {
+	node.Expression.Accept(w)
+	return nil
+}
+
+func (w *walker) VisitBlock(node *ast.BlockStatement) interface{} {
+	w.block = node
+	node.Expression.Accept(w)
+	w.block = nil
+
+	if node.Program != nil {
+		node.Program.Accept(w)
+	}
+	if node.Inverse != nil {
+		node.Inverse.Accept(w)
+	}
+	return nil
+}
+
+func (w *walker) VisitPartial(node *ast.PartialStatement) interface{} {
+	if w.onPartial != nil {
+		w.onPartial(node)
+	}
+	if subExpr, ok := node.Name.(*ast.SubExpression); ok {
+		subExpr.Accept(w)
+	}
+	for _, param := range node.Params {
+		param.Accept(w)
+	}
+	if node.Hash != nil {
+		node.Hash.Accept(w)
+	}
+	return nil
+}
+
+func (w *walker) VisitContent(node *ast.ContentStatement) interface{} {
+	return nil
+}
+
+func (w *walker) VisitComment(node *ast.CommentStatement) interface{} {
+	return nil
+}
+
+func (w *walker) VisitExpression(node *ast.Expression) interface{} {
+	block := w.block
+	w.block = nil
+	if w.onExpression != nil {
+		w.onExpression(node, block)
+	}
+
+	node.Path.Accept(w)
+	for _, param := range node.Params {
+		param.Accept(w)
+	}
+	if node.Hash != nil {
+		node.Hash.Accept(w)
+	}
+	return nil
+}
+
+func (w *walker) VisitSubExpression(node *ast.SubExpression) interface{} {
+	node.Expression.Accept(w)
+	return nil
+}
+
+func (w *walker) VisitPath(node *ast.PathExpression) interface{} {
+	if w.onPath != nil {
+		w.onPath(node)
+	}
+	return nil
+}
+
+func (w *walker) VisitString(node *ast.StringLiteral) interface{} {
+	return nil
+}
+
+func (w *walker) VisitBoolean(node *ast.BooleanLiteral) interface{} {
+	return nil
+}
+
+func (w *walker) VisitNumber(node *ast.NumberLiteral) interface{} {
+	return nil
+}
+
+func (w *walker) VisitHash(node *ast.Hash) interface{} {
+	for _, pair := range node.Pairs {
+		pair.Accept(w)
+	}
+	return nil
+}
+
+func (w *walker) VisitHashPair(node *ast.HashPair) interface{} {
+	node.Val.Accept(w)
+	return nil
+}
+
+// partialReference is a {{> partial}} in a template, with the byte offset of its name in the source.
+// Partials named by a subexpression are only known when rendering, so they aren't referenced statically.
+type partialReference struct {
+	name string
+	line int
+	pos  int
+}
+
+func partialReferences(program *ast.Program) []partialReference {
+	references := make([]partialReference, 0)
+	w := &walker{
+		onPartial: func(node *ast.PartialStatement) {
+			if name, ok := ast.HelperNameStr(node.Name); ok {
+				references = append(references, partialReference{name: name, line: node.Line, pos: namePos(node.Name)})
+			}
+		},
+	}
+	w.walk(program)
+	return references
+}
+
+// namePos returns the byte offset of a partial's name in the source. raymond positions a data path such as
+// @billing/row after its @, and a [bracketed] path at its opening bracket, neither of which is where the name starts.
+func namePos(node ast.Node) int {
+	pos := node.Location().Pos
+	if path, ok := node.(*ast.PathExpression); ok {
+		if path.Data {
+			return pos - 1
+		}
+		if strings.HasPrefix(path.Original, "[") {
+			return pos + 1
+		}
+	}
+	return pos
+}
+
+// requestUsage reports whether a template reads @request itself, and whether it renders a partial named by a
+// subexpression, which could be any partial.
+func requestUsage(program *ast.Program) (reads bool, dynamicPartials bool) {
+	w := &walker{
+		onPartial: func(node *ast.PartialStatement) {
+			if _, ok := node.Name.(*ast.SubExpression); ok {
+				dynamicPartials = true
+			}
+		},
+		onPath: func(node *ast.PathExpression) {
+			if node.Data && len(node.Parts) > 0 && node.Parts[0] == RequestDataKey {
+				reads = true
+			}
+		},
+	}
+	w.walk(program)
+	return reads, dynamicPartials
+}
+
+// requestScoped returns the templates reading @request, themselves or through the partials they render, whose
+// output can differ between requests rendering the same data. A template rendering a partial named by a
+// subexpression is request scoped when any partial is.
+func requestScoped(parsed map[templateKey]parsedTemplate, raws map[templateKey]echorend.RawTemplateData, has func(string) bool) map[templateKey]bool {
+	scoped := make(map[templateKey]bool)
+	anyPartial := false
+	for key, tmpl := range parsed {
+		if tmpl.request {
+			scoped[key] = true
+			anyPartial = anyPartial || key.role == rolePartial
+		}
+	}
+
+	// Partials can use partials, so follow references until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for key, tmpl := range parsed {
+			if scoped[key] {
+				continue
+			}
+			uses := tmpl.dynamicPartials && anyPartial
+			namespace, _ := echorend.SplitName(raws[key].TemplateName)
+			for _, reference := range tmpl.references {
+				uses = uses || scoped[templateKey{role: rolePartial, name: resolvePartial(namespace, reference.name, has)}]
+			}
+			if uses {
+				scoped[key] = true
+				anyPartial = anyPartial || key.role == rolePartial
+				changed = true
+			}
+		}
+	}
+	return scoped
+}
+
+// checkPartials statically finds every reference to a partial the renderer doesn't have. It also returns the
+// templates which can't render because of them, directly or through the partials they use.
+func (r *HandlebarsRenderer) checkPartials(set *templateSet) ([]error, map[templateKey]bool) {
+	raws := make(map[templateKey]echorend.RawTemplateData)
+	for key, tmpl := range set.analysedTemplates() {
+		raws[key] = tmpl.raw
+	}
+	return missingPartials(raws, set.hasPartial)
+}
+
+// CheckPartials statically finds every reference to a partial the renderer doesn't have, in every template the
+// last Setup or Reload parsed. Unlike CheckRenders, it checks the templates which parsed even when the build failed
+// on others, so tools such as echorend lint can report every problem in one run.
+func (r *HandlebarsRenderer) CheckPartials() []error {
+	r.buildMutex.Lock()
+	defer r.buildMutex.Unlock()
+	errs, _ := missingPartials(r.attempted, func(name string) bool {
+		return r.attemptedPartials[name]
+	})
+	return errs
+}
+
+func missingPartials(templates map[templateKey]echorend.RawTemplateData, has func(string) bool) ([]error, map[templateKey]bool) {
+	errs := make([]error, 0)
+	failing := make(map[templateKey]bool)
+	uses := make(map[templateKey][]string)
+	for key, raw := range templates {
+		program, err := parser.Parse(raw.TemplateData)
+		if err != nil {
+			continue
+		}
+		namespace, _ := echorend.SplitName(raw.TemplateName)
+		for _, reference := range partialReferences(program) {
+			name := resolvePartial(namespace, reference.name, has)
+			uses[key] = append(uses[key], name)
+			if has(name) {
+				continue
+			}
+			failing[key] = true
+			errs = append(errs, &echorend.TemplateError{
+				TemplateName: raw.TemplateName,
+				Source:       raw.Source,
+				Line:         reference.line,
+				Err:          fmt.Errorf("partial %s not found", reference.name),
+			})
+		}
+	}
+
+	// A template using a failing partial fails too, so follow uses until nothing changes.
+	for changed := true; changed; {
+		changed = false
+		for key, names := range uses {
+			for _, name := range names {
+				if failing[templateKey{role: rolePartial, name: name}] && !failing[key] {
+					failing[key] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	return errs, failing
+}
+
+// UnusedPartials returns the names of partials no view, layout or other partial references, sorted.
+// Partials named dynamically with a subexpression can't be seen, and a partial only rendered directly as a view
+// is reported too, so treat the result as a warning.
+func (r *HandlebarsRenderer) UnusedPartials() []string {
+	set := r.current()
+	used := make(map[string]bool)
+	for _, tmpl := range set.analysedTemplates() {
+		program, err := parser.Parse(tmpl.raw.TemplateData)
+		if err != nil {
+			continue
+		}
+		namespace, _ := echorend.SplitName(tmpl.raw.TemplateName)
+		for _, reference := range partialReferences(program) {
+			used[resolvePartial(namespace, reference.name, set.hasPartial)] = true
+		}
+	}
+
+	unused := make([]string, 0)
+	for name := range set.partials {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+func (s *templateSet) hasPartial(name string) bool {
+	_, ok := s.partials[name]
+	return ok
+}
+
+// analysedTemplates returns every view, partial and layout, keyed by role as well as name, as a view, partial and
+// layout can share a name.
+func (s *templateSet) analysedTemplates() map[templateKey]*compiledTemplate {
+	templates := make(map[templateKey]*compiledTemplate)
+	for name, tmpl := range s.templates {
+		if tmpl.role == roleView {
+			templates[templateKey{role: roleView, name: name}] = tmpl
+		}
+	}
+	for name, tmpl := range s.partials {
+		templates[templateKey{role: rolePartial, name: name}] = tmpl
+	}
+	for name, tmpl := range s.layouts {
+		templates[templateKey{role: roleLayout, name: name}] = tmpl
+	}
+	return templates
+}
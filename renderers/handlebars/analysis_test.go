@@ -0,0 +1,174 @@
+package handlebars_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+)
+
+func TestHandlebarsCheckRenders_MissingPartialInsideFalseIf_ReturnsError(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "static-view1",
+		TemplateData: "<HTML>\n{{#if user}}\n{{> missing-partial1}}\n{{/if}}</HTML>",
+		Source:       "views/static-view1.hbs",
+	})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, NewMockTemplateGatherer())
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(errs[0], &templateErr) {
+		t.Fatalf("Expected TemplateError, got %v", errs[0])
+	}
+	if templateErr.TemplateName != "static-view1" || templateErr.Source != "views/static-view1.hbs" || templateErr.Line != 3 {
+		t.Errorf("Unexpected error location %+v", templateErr)
+	}
+}
+
+func TestHandlebarsCheckRenders_MissingPartialInsideEmptyEachAndElse_ReturnsErrors(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "static-view2",
+		TemplateData: "{{#each items}}{{> missing-partial2}}{{else}}{{> missing-partial3}}{{/each}}",
+	})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, NewMockTemplateGatherer())
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %v", errs)
+	}
+}
+
+func TestHandlebarsCheckRenders_PartialUsesMissingPartial_ReportsOnlyThePartial(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "static-view3",
+		TemplateData: "{{> static-partial3}}",
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "static-partial3",
+		TemplateData: "{{> missing-partial4}}",
+	})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, partialGatherer)
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(errs[0], &templateErr) || templateErr.TemplateName != "static-partial3" {
+		t.Errorf("Expected the error to be reported against the partial, got %v", errs[0])
+	}
+}
+
+func TestHandlebarsCheckRenders_MissingPartialInLayoutBlock_ReturnsError(t *testing.T) {
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "static-layout",
+		TemplateData: "{{#unless hideNav}}{{> missing-nav}}{{/unless}}{{{@body}}}",
+	})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		LayoutGatherer: layoutGatherer,
+	})
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %v", errs)
+	}
+}
+
+func TestHandlebarsCheckRenders_ViewAndPartialShareName_ChecksView(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "card", TemplateData: "{{#if x}}{{> missing}}{{/if}}"})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "card", TemplateData: "card"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		CollisionPolicy: echorend.CollisionFirstWins,
+	})
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(errs[0], &templateErr) || templateErr.TemplateName != "card" {
+		t.Errorf("Expected the missing partial to be reported against the view, got %v", errs[0])
+	}
+}
+
+func TestHandlebarsCheckRenders_ViewNamespacedLikeLayout_ChecksViewAndLayout(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "layout:main", TemplateData: "{{#if x}}{{> missing-view-partial}}{{/if}}"})
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "main", TemplateData: "{{#if x}}{{> missing-layout-partial}}{{/if}}{{{@body}}}"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:   viewGatherer,
+		LayoutGatherer: layoutGatherer,
+	})
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 2 {
+		t.Errorf("Expected an error for the view and the layout, got %v", errs)
+	}
+}
+
+func TestHandlebarsCheckPartials_SetupFailed_ChecksTemplatesWhichParsed(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "broken", TemplateData: "{{#if}}"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "page", TemplateData: "line one\n{{> missing}}", Source: "views/page.hbs"})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, NewMockTemplateGatherer())
+	if err := renderer.Setup(); err == nil {
+		t.Fatalf("Expected the broken view to fail setup")
+	}
+
+	errs := renderer.CheckPartials()
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(errs[0], &templateErr) || templateErr.Source != "views/page.hbs" || templateErr.Line != 2 {
+		t.Errorf("Expected the missing partial in views/page.hbs:2, got %v", errs[0])
+	}
+}
+
+func TestHandlebarsUnusedPartials_SomePartialsUnreferenced_ReturnsThemSorted(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "static-view5",
+		TemplateData: "{{#if x}}{{> used-partial}}{{/if}}",
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "used-partial", TemplateData: "{{> nested-used-partial}}"})
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "nested-used-partial", TemplateData: "used"})
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "unused-b", TemplateData: "unused"})
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "unused-a", TemplateData: "unused"})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, partialGatherer)
+	renderer.MustSetup()
+
+	unused := renderer.UnusedPartials()
+
+	if len(unused) != 2 || unused[0] != "unused-a" || unused[1] != "unused-b" {
+		t.Errorf("Expected [unused-a unused-b], got %v", unused)
+	}
+}
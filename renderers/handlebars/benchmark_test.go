@@ -0,0 +1,99 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/aymerick/raymond"
+)
+
+// largeView renders a row partial for every item, giving around 100KB of output for 1000 items.
+const largeView = "<table>{{#each items}}{{> bench-row}}{{/each}}</table>"
+
+const benchRow = "<tr><td>{{id}}</td><td>{{name}}</td><td>{{description}}</td></tr>\n"
+
+func largeData() map[string]interface{} {
+	items := make([]map[string]interface{}, 1000)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"id":          i,
+			"name":        "item name",
+			"description": strings.Repeat("description ", 5),
+		}
+	}
+	return map[string]interface{}{"items": items}
+}
+
+func newBenchRenderer(b *testing.B) *handlebars.HandlebarsRenderer {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "bench-view", TemplateData: largeView})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "bench-row", TemplateData: benchRow})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, partialGatherer)
+	if err := renderer.Setup(); err != nil {
+		b.Fatal(err)
+	}
+	return renderer
+}
+
+// writerOnly hides any WriteString method, forcing the copying fallback.
+type writerOnly struct {
+	io.Writer
+}
+
+func BenchmarkHandlebarsRendererRender_LargeTemplate(b *testing.B) {
+	renderer := newBenchRenderer(b)
+	data := largeData()
+	buf := new(bytes.Buffer)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := renderer.Render(buf, "bench-view", data, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHandlebarsRendererRender_LargeTemplateWriterOnly(b *testing.B) {
+	renderer := newBenchRenderer(b)
+	data := largeData()
+	buf := new(bytes.Buffer)
+	w := writerOnly{buf}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := renderer.Render(w, "bench-view", data, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRaymondExec_LargeTemplateCopied is the baseline of executing the template and copying the string
+// into the writer as a []byte.
+func BenchmarkRaymondExec_LargeTemplateCopied(b *testing.B) {
+	tmpl := raymond.MustParse(largeView)
+	tmpl.RegisterPartial("bench-row", benchRow)
+	data := largeData()
+	buf := new(bytes.Buffer)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		str, err := tmpl.Exec(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := buf.Write([]byte(str)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
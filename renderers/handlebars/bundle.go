@@ -0,0 +1,79 @@
+package handlebars
+
+import (
+	"sort"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/bundle"
+	"github.com/aymerick/raymond/parser"
+)
+
+// NewHandlebarsRendererFromBundle creates a renderer which gathers its views, partials and layouts from a bundle
+// rather than the filesystem. The config's gatherers are replaced, and its DefaultLayout defaults to the bundle's.
+// Its CollisionPolicy is ignored: collisions were resolved by the policy of the renderer the bundle was made from,
+// so the renderer uses CollisionFirstWins to render the templates as that one did.
+// raymond can't serialize parsed templates, so Setup still parses every template, but skips walking and reading files.
+func NewHandlebarsRendererFromBundle(b *bundle.Bundle, config HandlebarsRendererConfig) *HandlebarsRenderer {
+	config.ViewGatherer = b.Gatherer(bundle.RoleView)
+	config.PartialGatherer = b.Gatherer(bundle.RolePartial)
+	config.LayoutGatherer = b.Gatherer(bundle.RoleLayout)
+	if config.DefaultLayout == "" {
+		config.DefaultLayout = b.DefaultLayout
+	}
+	// Bundles only hold the templates that won any collision, except a view kept over a partial of the same name,
+	// which the partial still sits beside.
+	config.CollisionPolicy = echorend.CollisionFirstWins
+	return NewHandlebarsRendererWithConfig(config)
+}
+
+// Bundle returns the current template set as a bundle, for NewHandlebarsRendererFromBundle to load. The set is
+// checked with CheckRenders first, and any problems found are returned as an *echorend.SetupError instead.
+func (r *HandlebarsRenderer) Bundle() (*bundle.Bundle, error) {
+	set := r.current()
+	if errs := r.CheckRenders(); len(errs) > 0 {
+		return nil, &echorend.SetupError{Errors: errs}
+	}
+
+	b := &bundle.Bundle{
+		Version:       bundle.Version,
+		DefaultLayout: echorend.CanonicalName(r.config.DefaultLayout),
+		Templates:     make([]bundle.Template, 0),
+	}
+	for _, entry := range set.sorted() {
+		raw := entry.tmpl.raw
+		hash := raw.Hash
+		if hash == "" {
+			hash = echorend.ContentHash(raw.TemplateData)
+		}
+		b.Templates = append(b.Templates, bundle.Template{
+			Role:     entry.tmpl.role.String(),
+			Name:     entry.name,
+			Source:   raw.Source,
+			Gatherer: raw.Gatherer,
+			Hash:     hash,
+			Data:     raw.TemplateData,
+			Partials: usedPartials(set, raw),
+		})
+	}
+	return b, nil
+}
+
+// usedPartials returns the names of the partials a template references, resolved and sorted.
+func usedPartials(set *templateSet, raw echorend.RawTemplateData) []string {
+	program, err := parser.Parse(raw.TemplateData)
+	if err != nil {
+		return nil
+	}
+	namespace, _ := echorend.SplitName(raw.TemplateName)
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, reference := range partialReferences(program) {
+		name := resolvePartial(namespace, reference.name, set.hasPartial)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,147 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/bundle"
+	"github.com/BlindGarret/echorend/gatherers/namespace"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+)
+
+func newBundledConfig() handlebars.HandlebarsRendererConfig {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "index", TemplateData: "{{> line}}|{{> billing:summary}}", Source: "views/index.hbs"})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "line", TemplateData: "line view"})
+	partials := NewMockTemplateGatherer()
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "line", TemplateData: "app line"})
+	billingPartials := NewMockTemplateGatherer()
+	billingPartials.SetTemplates(
+		echorend.RawTemplateData{TemplateName: "line", TemplateData: "billing line"},
+		echorend.RawTemplateData{TemplateName: "summary", TemplateData: "summary of {{> line}}"},
+	)
+	layouts := NewMockTemplateGatherer()
+	layouts.AddTemplate(echorend.RawTemplateData{TemplateName: "main", TemplateData: "<main>{{{@body}}}</main>"})
+	return handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    views,
+		PartialGatherer: combined(partials, namespace.NewNamespacedGatherer("billing", billingPartials)),
+		LayoutGatherer:  layouts,
+		DefaultLayout:   "main",
+		CollisionPolicy: echorend.CollisionFirstWins,
+	}
+}
+
+func roundTrip(t *testing.T, b *bundle.Bundle) *bundle.Bundle {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := bundle.Write(buf, b); err != nil {
+		t.Fatalf("Expected no error writing bundle, got %v", err)
+	}
+	read, err := bundle.Read(buf)
+	if err != nil {
+		t.Fatalf("Expected no error reading bundle, got %v", err)
+	}
+	return read
+}
+
+func TestHandlebarsRendererBundle_FromBundle_RendersAsOriginal(t *testing.T) {
+	original := handlebars.NewHandlebarsRendererWithConfig(newBundledConfig())
+	original.MustSetup()
+	b, err := original.Bundle()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	renderer := handlebars.NewHandlebarsRendererFromBundle(roundTrip(t, b), handlebars.HandlebarsRendererConfig{})
+	renderer.MustSetup()
+
+	for _, name := range []string{"index", "line", "billing:summary"} {
+		expected, err := renderToString(name, nil, original)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		got, err := renderToString(name, nil, renderer)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got != expected {
+			t.Errorf("Expected %s to render %q, got %q", name, expected, got)
+		}
+	}
+	got, err := renderToString("index", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != "<main>app line|summary of billing line</main>" {
+		t.Errorf("Unexpected render %q", got)
+	}
+}
+
+func TestHandlebarsRendererBundle_Bundle_RecordsRelationships(t *testing.T) {
+	renderer := handlebars.NewHandlebarsRendererWithConfig(newBundledConfig())
+	renderer.MustSetup()
+
+	b, err := renderer.Bundle()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if b.DefaultLayout != "main" {
+		t.Errorf("Expected default layout main, got %q", b.DefaultLayout)
+	}
+	partials := make(map[string][]string)
+	for _, template := range b.Templates {
+		partials[template.Role+" "+template.Name] = template.Partials
+		if template.Hash != echorend.ContentHash(template.Data) {
+			t.Errorf("Expected %s hashed, got %q", template.Name, template.Hash)
+		}
+	}
+	if got := partials["view index"]; len(got) != 2 || got[0] != "billing:summary" || got[1] != "line" {
+		t.Errorf("Expected index to use billing:summary and line, got %v", got)
+	}
+	if got := partials["partial billing:summary"]; len(got) != 1 || got[0] != "billing:line" {
+		t.Errorf("Expected billing:summary to use billing:line, got %v", got)
+	}
+}
+
+func TestHandlebarsRendererBundle_FailingTemplates_ReturnsSetupError(t *testing.T) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "index", TemplateData: "{{> missing}}"})
+	renderer := handlebars.NewHandlebarsRenderer(views, NewMockTemplateGatherer())
+	renderer.MustSetup()
+
+	_, err := renderer.Bundle()
+
+	var setupErr *echorend.SetupError
+	if !errors.As(err, &setupErr) {
+		t.Fatalf("Expected SetupError, got %v", err)
+	}
+}
+
+func TestHandlebarsRendererBundle_ConfigDefaultLayout_OverridesBundle(t *testing.T) {
+	original := handlebars.NewHandlebarsRendererWithConfig(newBundledConfig())
+	original.MustSetup()
+	b, err := original.Bundle()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b.Templates = append(b.Templates, bundle.Template{
+		Role: bundle.RoleLayout,
+		Name: "plain",
+		Hash: echorend.ContentHash("{{{@body}}}"),
+		Data: "{{{@body}}}",
+	})
+
+	renderer := handlebars.NewHandlebarsRendererFromBundle(b, handlebars.HandlebarsRendererConfig{DefaultLayout: "plain"})
+	renderer.MustSetup()
+
+	got, err := renderToString("line", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != "line view" {
+		t.Errorf("Expected the configured layout, got %q", got)
+	}
+}
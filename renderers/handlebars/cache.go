@@ -0,0 +1,230 @@
+package handlebars
+
+import (
+	"container/list"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/labstack/echo/v4"
+)
+
+// RenderCacheConfig configures caching of rendered output, keyed by the template name, locale, layout and a key of
+// the render's data. Renders reading @request, in the view, its layout or any partial they use, are never cached,
+// as values such as @request.csrf differ between requests with the same data. Helpers reading the request
+// themselves aren't seen, so have Key return false for the templates using them.
+type RenderCacheConfig struct {
+	// MaxEntries bounds the number of cached renders, evicting the least recently used. Defaults to 1000.
+	MaxEntries int
+	// TTL is how long a cached render is served for. Zero keeps renders until they are evicted or templates reload.
+	TTL time.Duration
+	// Key returns the data key of a render, or false to render without the cache. It defaults to DataKey.
+	Key func(name string, data interface{}, c echo.Context) (string, bool)
+	// Clock returns the current time, defaulting to time.Now.
+	Clock func() time.Time
+}
+
+// CacheKeyer is implemented by data which supplies its own cache key, rather than being hashed by DataKey.
+type CacheKeyer interface {
+	CacheKey() string
+}
+
+// DataKey is the default RenderCacheConfig.Key. Data implementing CacheKeyer is keyed by its CacheKey, and any
+// other data by a hash of its JSON encoding, so only exported fields tell data apart. Data which can't be encoded
+// as JSON isn't cached.
+func DataKey(_ string, data interface{}, _ echo.Context) (string, bool) {
+	if keyer, ok := data.(CacheKeyer); ok {
+		return "key:" + keyer.CacheKey(), true
+	}
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return "", false
+	}
+	return "json:" + echorend.ContentHash(string(bs)), true
+}
+
+// Stats are counters of a renderer's activity since it was created.
+type Stats struct {
+	CacheHits      uint64
+	CacheMisses    uint64
+	CacheEvictions uint64
+	// CacheEntries is the number of renders cached for the current template set.
+	CacheEntries int
+}
+
+// renderStats are the counters behind Stats, kept across template sets. They are only accessed atomically.
+type renderStats struct {
+	cacheHits      uint64
+	cacheMisses    uint64
+	cacheEvictions uint64
+}
+
+// Stats returns the renderer's counters.
+func (r *HandlebarsRenderer) Stats() Stats {
+	stats := Stats{
+		CacheHits:      atomic.LoadUint64(&r.stats.cacheHits),
+		CacheMisses:    atomic.LoadUint64(&r.stats.cacheMisses),
+		CacheEvictions: atomic.LoadUint64(&r.stats.cacheEvictions),
+	}
+	if cache := r.current().cache; cache != nil {
+		stats.CacheEntries = cache.len()
+	}
+	return stats
+}
+
+// renderCached renders through the set's cache, when caching is enabled and the render has a key.
+func (r *HandlebarsRenderer) renderCached(set *templateSet, w io.Writer, name string, data interface{}, c echo.Context, bare bool) error {
+	if set.cache == nil {
+		return r.render(set, w, name, data, c, bare)
+	}
+	layout := ""
+	if !bare {
+		layout = r.layoutName(data, c)
+	}
+	locale := r.locale(c)
+	if set.readsRequest(name, layout, locale) {
+		return r.render(set, w, name, data, c, bare)
+	}
+	dataKey, ok := r.config.Cache.Key(name, data, c)
+	if !ok {
+		return r.render(set, w, name, data, c, bare)
+	}
+	key := strings.Join([]string{echorend.CanonicalName(name), locale, layout, dataKey}, "\x00")
+
+	now := r.config.Cache.Clock()
+	if output, ok := set.cache.get(key, now); ok {
+		atomic.AddUint64(&r.stats.cacheHits, 1)
+		_, err := io.WriteString(w, output)
+		return err
+	}
+	atomic.AddUint64(&r.stats.cacheMisses, 1)
+
+	buf := new(strings.Builder)
+	if err := r.render(set, buf, name, data, c, bare); err != nil {
+		return err
+	}
+	if set.cache.put(key, buf.String(), now) {
+		atomic.AddUint64(&r.stats.cacheEvictions, 1)
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// readsRequest reports whether rendering name with layout reads @request, as render would resolve them, so its
+// output can't be shared between requests.
+func (s *templateSet) readsRequest(name, layout, locale string) bool {
+	view, fragment := SplitFragment(echorend.CanonicalName(name))
+	tmpl, ok := localized(s.templates, view, locale)
+	if !ok {
+		return false
+	}
+	if tmpl.request {
+		return true
+	}
+	if fragment != "" {
+		namespace, _ := echorend.SplitName(view)
+		partial, ok := localized(s.templates, resolvePartial(namespace, fragment, s.hasPartial), locale)
+		return !tmpl.fragments[fragment] && ok && partial.request
+	}
+	if tmpl.role == rolePartial || layout == "" {
+		return false
+	}
+	tmpl, ok = localized(s.layouts, echorend.CanonicalName(layout), locale)
+	return ok && tmpl.request
+}
+
+// renderCache is a size bounded LRU cache of rendered output. Each template set has its own, so reloading
+// templates starts with an empty cache, and renders still finishing with the previous set can't fill the new one.
+type renderCache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	entries    map[string]*list.Element
+	// recent orders entries from most to least recently used.
+	recent *list.List
+}
+
+type cacheEntry struct {
+	key     string
+	output  string
+	expires time.Time
+}
+
+func newRenderCache(config *RenderCacheConfig) *renderCache {
+	if config == nil {
+		return nil
+	}
+	return &renderCache{
+		maxEntries: config.MaxEntries,
+		ttl:        config.TTL,
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+func (rc *renderCache) get(key string, now time.Time) (string, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	element, ok := rc.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && !now.Before(entry.expires) {
+		rc.recent.Remove(element)
+		delete(rc.entries, key)
+		return "", false
+	}
+	rc.recent.MoveToFront(element)
+	return entry.output, true
+}
+
+// put caches a render, returning whether the least recently used render was evicted to make room.
+func (rc *renderCache) put(key string, output string, now time.Time) bool {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	entry := &cacheEntry{key: key, output: output}
+	if rc.ttl > 0 {
+		entry.expires = now.Add(rc.ttl)
+	}
+	if element, ok := rc.entries[key]; ok {
+		element.Value = entry
+		rc.recent.MoveToFront(element)
+		return false
+	}
+	rc.entries[key] = rc.recent.PushFront(entry)
+	if rc.recent.Len() <= rc.maxEntries {
+		return false
+	}
+	oldest := rc.recent.Back()
+	rc.recent.Remove(oldest)
+	delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	return true
+}
+
+func (rc *renderCache) len() int {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	return rc.recent.Len()
+}
+
+func defaultRenderCacheConfig(config *RenderCacheConfig) *RenderCacheConfig {
+	if config == nil {
+		return nil
+	}
+	defaulted := *config
+	if defaulted.MaxEntries <= 0 {
+		defaulted.MaxEntries = 1000
+	}
+	if defaulted.Key == nil {
+		defaulted.Key = DataKey
+	}
+	if defaulted.Clock == nil {
+		defaulted.Clock = time.Now
+	}
+	return &defaulted
+}
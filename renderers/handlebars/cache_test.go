@@ -0,0 +1,271 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func newCachedRenderer(config handlebars.RenderCacheConfig) (*handlebars.HandlebarsRenderer, *MockTemplateGatherer) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "a", TemplateData: "a {{name}}"})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "b", TemplateData: "b {{name}}"})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "c", TemplateData: "c {{name}}"})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "broken", TemplateData: "{{> missing}}"})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "form", TemplateData: "<form>{{> token}}</form>"})
+	partials := NewMockTemplateGatherer()
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "token", TemplateData: "{{#with @request}}{{csrf}}{{/with}}"})
+	layouts := NewMockTemplateGatherer()
+	layouts.AddTemplate(echorend.RawTemplateData{TemplateName: "main", TemplateData: "<main>{{{@body}}}</main>"})
+	layouts.AddTemplate(echorend.RawTemplateData{TemplateName: "nav", TemplateData: "<nav>{{@request.csrf}}</nav>{{{@body}}}"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    views,
+		PartialGatherer: partials,
+		LayoutGatherer:  layouts,
+		Cache:           &config,
+	})
+	renderer.MustSetup()
+	return renderer, views
+}
+
+type keyedPage struct {
+	Name string
+}
+
+func (p keyedPage) CacheKey() string {
+	return "page"
+}
+
+func TestHandlebarsRendererCache_SameData_ServesCachedRender(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{})
+
+	first, err := renderToString("a", map[string]interface{}{"name": "World"}, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := renderToString("a", map[string]interface{}{"name": "World"}, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	third, err := renderToString("a", map[string]interface{}{"name": "Other"}, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first != "a World" || second != first || third != "a Other" {
+		t.Errorf("Unexpected renders %q, %q, %q", first, second, third)
+	}
+	stats := renderer.Stats()
+	if stats.CacheHits != 1 || stats.CacheMisses != 2 || stats.CacheEntries != 2 {
+		t.Errorf("Expected 1 hit, 2 misses and 2 entries, got %+v", stats)
+	}
+}
+
+func TestHandlebarsRendererCache_CacheKeyer_KeysByCacheKey(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{})
+
+	first, err := renderToString("a", keyedPage{Name: "First"}, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := renderToString("a", keyedPage{Name: "Second"}, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first != "a First" || second != "a First" {
+		t.Errorf("Expected the render cached by its key, got %q and %q", first, second)
+	}
+}
+
+func TestHandlebarsRendererCache_TTL_ExpiresRenders(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{TTL: time.Minute, Clock: clock.Now})
+
+	if _, err := renderToString("a", nil, renderer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clock.now = clock.now.Add(59 * time.Second)
+	if _, err := renderToString("a", nil, renderer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if _, err := renderToString("a", nil, renderer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := renderer.Stats()
+	if stats.CacheHits != 1 || stats.CacheMisses != 2 {
+		t.Errorf("Expected the render to expire after a minute, got %+v", stats)
+	}
+}
+
+func TestHandlebarsRendererCache_MaxEntries_EvictsLeastRecentlyUsed(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{MaxEntries: 2})
+
+	for _, name := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := renderToString(name, nil, renderer); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	stats := renderer.Stats()
+	if stats.CacheHits != 2 || stats.CacheMisses != 4 || stats.CacheEvictions != 2 || stats.CacheEntries != 2 {
+		t.Errorf("Expected b then c evicted, got %+v", stats)
+	}
+}
+
+func TestHandlebarsRendererCache_Reload_ClearsCache(t *testing.T) {
+	renderer, views := newCachedRenderer(handlebars.RenderCacheConfig{})
+	if _, err := renderToString("a", nil, renderer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	views.SetTemplates(echorend.RawTemplateData{TemplateName: "a", TemplateData: "changed"})
+
+	if err := renderer.Reload(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stats := renderer.Stats(); stats.CacheEntries != 0 {
+		t.Errorf("Expected an empty cache after reload, got %+v", stats)
+	}
+	got, err := renderToString("a", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != "changed" {
+		t.Errorf("Expected the reloaded template, got %q", got)
+	}
+}
+
+func TestHandlebarsRendererCache_Layout_IsPartOfKey(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{})
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+
+	plain, err := renderToStringWithContext("a", nil, renderer, c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	c.Set(handlebars.LayoutKey, "main")
+	wrapped, err := renderToStringWithContext("a", nil, renderer, c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if plain != "a " || wrapped != "<main>a </main>" {
+		t.Errorf("Expected each layout cached separately, got %q and %q", plain, wrapped)
+	}
+}
+
+func TestHandlebarsRendererCache_RequestValuesInLayout_RendersUncached(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{})
+	first := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	first.Set(handlebars.LayoutKey, "nav")
+	first.Set("csrf", "first-token")
+	second := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	second.Set(handlebars.LayoutKey, "nav")
+	second.Set("csrf", "second-token")
+
+	firstOut, err := renderToStringWithContext("a", nil, renderer, first)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	secondOut, err := renderToStringWithContext("a", nil, renderer, second)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if firstOut != "<nav>first-token</nav>a " || secondOut != "<nav>second-token</nav>a " {
+		t.Errorf("Expected each request's token, got %q and %q", firstOut, secondOut)
+	}
+	if stats := renderer.Stats(); stats != (handlebars.Stats{}) {
+		t.Errorf("Expected the cache unused, got %+v", stats)
+	}
+}
+
+func TestHandlebarsRendererCache_RequestValuesInPartial_RendersUncached(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{})
+	first := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	first.Set("csrf", "first-token")
+	second := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	second.Set("csrf", "second-token")
+
+	firstOut, err := renderToStringWithContext("form", nil, renderer, first)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	secondOut, err := renderToStringWithContext("form", nil, renderer, second)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, c := range []echo.Context{second, first} {
+		if _, err := renderToStringWithContext("a", nil, renderer, c); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if firstOut != "<form>first-token</form>" || secondOut != "<form>second-token</form>" {
+		t.Errorf("Expected each request's token, got %q and %q", firstOut, secondOut)
+	}
+	if stats := renderer.Stats(); stats.CacheHits != 1 || stats.CacheMisses != 1 {
+		t.Errorf("Expected only the view without request values cached, got %+v", stats)
+	}
+}
+
+func TestHandlebarsRendererCache_KeyDeclines_RendersUncached(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{
+		Key: func(name string, data interface{}, c echo.Context) (string, bool) {
+			return "", false
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := renderToString("a", nil, renderer); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if stats := renderer.Stats(); stats != (handlebars.Stats{}) {
+		t.Errorf("Expected the cache unused, got %+v", stats)
+	}
+}
+
+func TestHandlebarsRendererCache_UnencodableData_RendersUncached(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{})
+
+	if _, err := renderToString("a", map[string]interface{}{"name": "World", "fn": func() {}}, renderer); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stats := renderer.Stats(); stats.CacheMisses != 0 || stats.CacheEntries != 0 {
+		t.Errorf("Expected the cache unused, got %+v", stats)
+	}
+}
+
+func TestHandlebarsRendererCache_FailedRender_IsNotCached(t *testing.T) {
+	renderer, _ := newCachedRenderer(handlebars.RenderCacheConfig{})
+
+	for i := 0; i < 2; i++ {
+		if err := renderer.Render(new(bytes.Buffer), "broken", nil, nil); err == nil {
+			t.Fatalf("Expected an error")
+		}
+	}
+
+	stats := renderer.Stats()
+	if stats.CacheHits != 0 || stats.CacheMisses != 2 || stats.CacheEntries != 0 {
+		t.Errorf("Expected failed renders never cached, got %+v", stats)
+	}
+}
@@ -0,0 +1,27 @@
+package handlebars
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/BlindGarret/echorend"
+)
+
+// parseErrorLine matches the line raymond prefixes its parse errors with.
+var parseErrorLine = regexp.MustCompile(`^Parse error on line (\d+):\n`)
+
+// newTemplateError locates a raymond parse error within its template. raymond reports lines but not columns.
+func newTemplateError(data echorend.RawTemplateData, err error) *echorend.TemplateError {
+	templateErr := &echorend.TemplateError{
+		TemplateName: data.TemplateName,
+		Source:       data.Source,
+		Err:          err,
+	}
+	message := err.Error()
+	if match := parseErrorLine.FindStringSubmatch(message); match != nil {
+		templateErr.Line, _ = strconv.Atoi(match[1])
+		templateErr.Err = errors.New(message[len(match[0]):])
+	}
+	return templateErr
+}
@@ -0,0 +1,116 @@
+package handlebars_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+)
+
+func newBrokenRenderer(gatherErr error) *handlebars.HandlebarsRenderer {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "broken-view1",
+		TemplateData: "<HTML>\n{{herp}</HTML>",
+		Source:       "views/broken-view1.hbs",
+	})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "fine-view",
+		TemplateData: "<HTML></HTML>",
+		Source:       "views/fine-view.hbs",
+	})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "broken-view2",
+		TemplateData: "{{#if}}",
+		Source:       "views/broken-view2.hbs",
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "broken-partial",
+		TemplateData: "<h1>{{herp}</h1>",
+		Source:       "partials/broken-partial.hbs",
+	})
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.SetError(gatherErr)
+	return handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		LayoutGatherer:  layoutGatherer,
+	})
+}
+
+func TestHandlebarsRendererSetup_SeveralBrokenTemplates_ReportsEveryOne(t *testing.T) {
+	renderer := newBrokenRenderer(nil)
+
+	err := renderer.Setup()
+
+	var setupErr *echorend.SetupError
+	if !errors.As(err, &setupErr) {
+		t.Fatalf("Expected SetupError, got %v", err)
+	}
+	templateErrs := setupErr.TemplateErrors()
+	if len(templateErrs) != 3 {
+		t.Fatalf("Expected 3 template errors, got %d: %v", len(templateErrs), err)
+	}
+	expected := []struct {
+		name   string
+		source string
+		line   int
+	}{
+		{"broken-view1", "views/broken-view1.hbs", 2},
+		{"broken-view2", "views/broken-view2.hbs", 1},
+		{"broken-partial", "partials/broken-partial.hbs", 1},
+	}
+	for i, e := range expected {
+		if templateErrs[i].TemplateName != e.name || templateErrs[i].Source != e.source || templateErrs[i].Line != e.line {
+			t.Errorf("Expected %s from %s on line %d, got %+v", e.name, e.source, e.line, templateErrs[i])
+		}
+	}
+}
+
+func TestHandlebarsRendererSetup_GathererAndTemplateErrors_ErrorsIsSeesGathererError(t *testing.T) {
+	expectedErr := errors.New("test error")
+	renderer := newBrokenRenderer(expectedErr)
+
+	err := renderer.Setup()
+
+	if !errors.Is(err, expectedErr) {
+		t.Errorf("Expected error %v, got %v", expectedErr, err)
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(err, &templateErr) {
+		t.Errorf("Expected errors.As to find a TemplateError in %v", err)
+	}
+}
+
+func TestHandlebarsRendererSetup_BrokenTemplates_ReportListsLocations(t *testing.T) {
+	renderer := newBrokenRenderer(nil)
+
+	err := renderer.Setup()
+
+	report := err.Error()
+	for _, location := range []string{"views/broken-view1.hbs:2: broken-view1", "views/broken-view2.hbs:1: broken-view2", "partials/broken-partial.hbs:1: broken-partial"} {
+		if !strings.Contains(report, location) {
+			t.Errorf("Expected report to contain %s, got:\n%s", location, report)
+		}
+	}
+}
+
+func TestHandlebarsRendererMustSetup_BrokenTemplates_PanicsWithFullReport(t *testing.T) {
+	renderer := newBrokenRenderer(nil)
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("Expected panic, got nil")
+		}
+		report := fmt.Sprint(r)
+		if !strings.Contains(report, "broken-view1") || !strings.Contains(report, "broken-partial") {
+			t.Errorf("Expected panic with every error, got %s", report)
+		}
+	}()
+	renderer.MustSetup()
+}
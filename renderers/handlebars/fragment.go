@@ -0,0 +1,138 @@
+package handlebars
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/aymerick/raymond"
+	"github.com/aymerick/raymond/ast"
+	"github.com/aymerick/raymond/parser"
+	"github.com/labstack/echo/v4"
+)
+
+// FragmentSeparator separates a view from the fragment of it to render, as in page#results.
+const FragmentSeparator = "#"
+
+// FragmentHelper is the block helper naming a fragment of a view, which renders in place as usual. Helpers take
+// precedence over data fields, so its name can't be a field's:
+//
+//	{{#define-fragment "results"}}...{{/define-fragment}}
+const FragmentHelper = "define-fragment"
+
+// htmx request headers, see HandlebarsRendererConfig.HTMX.
+const (
+	HXRequestHeader = "HX-Request"
+	HXTargetHeader  = "HX-Target"
+)
+
+// fragment captures the named fragment's content when it is the one being rendered.
+func fragment(name string, options *raymond.Options) raymond.SafeString {
+	content := options.Fn()
+	state := stateFromOptions(options)
+	if state.fragment == name && !state.captured {
+		state.fragmentOutput = content
+		state.captured = true
+	}
+	return raymond.SafeString(content)
+}
+
+// SplitFragment splits a fragment selector such as page#results into the view and fragment names. The fragment is
+// empty when the name doesn't select one.
+func SplitFragment(name string) (view string, fragment string) {
+	if i := strings.LastIndex(name, FragmentSeparator); i >= 0 {
+		return name[:i], name[i+len(FragmentSeparator):]
+	}
+	return name, ""
+}
+
+// htmxSelector selects the fragment or partial an htmx request targets, when the view defines or renders one of
+// that name. Otherwise, and for names already selecting a fragment, the name is returned unchanged.
+func (r *HandlebarsRenderer) htmxSelector(set *templateSet, name string, c echo.Context) string {
+	if !r.config.HTMX || c == nil || c.Request() == nil || c.Request().Header.Get(HXRequestHeader) != "true" {
+		return name
+	}
+	target := c.Request().Header.Get(HXTargetHeader)
+	if target == "" || strings.Contains(name, FragmentSeparator) {
+		return name
+	}
+	view := echorend.CanonicalName(name)
+	tmpl, ok := set.templates[view]
+	if !ok {
+		return name
+	}
+	if tmpl.fragments[target] || set.rendersPartial(view, tmpl, target) {
+		return view + FragmentSeparator + target
+	}
+	return name
+}
+
+// rendersPartial reports whether a template renders the named partial itself, as it would be resolved from the
+// template's namespace.
+func (s *templateSet) rendersPartial(name string, tmpl *compiledTemplate, partial string) bool {
+	namespace, _ := echorend.SplitName(name)
+	target := resolvePartial(namespace, partial, s.hasPartial)
+	if !s.hasPartial(target) {
+		return false
+	}
+	for _, reference := range tmpl.references {
+		if resolvePartial(namespace, reference.name, s.hasPartial) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// renderFragment renders just one fragment of a view with the view's data, or the partial of that name when the view
+// has no such fragment, never in a layout. The view is still executed to give the fragment its context, but only the
+// fragment is written.
+func (r *HandlebarsRenderer) renderFragment(set *templateSet, w io.Writer, view string, tmpl *compiledTemplate, name string, data interface{}, state *renderState) error {
+	if !tmpl.fragments[name] {
+		namespace, _ := echorend.SplitName(view)
+		partialName := resolvePartial(namespace, name, set.hasPartial)
+		if partial, ok := localized(set.templates, partialName, state.locale); ok && partial.role == rolePartial {
+			str, err := partial.tmpl.ExecWith(data, state.frame())
+			if err != nil {
+				return fmt.Errorf("rendering %s from %s: %w", partialName, partial.raw.Source, err)
+			}
+			_, err = io.WriteString(w, str)
+			return err
+		}
+	}
+
+	state.fragment = name
+	if _, err := tmpl.tmpl.ExecWith(data, state.frame()); err != nil {
+		return fmt.Errorf("rendering %s%s%s from %s: %w", view, FragmentSeparator, name, tmpl.raw.Source, err)
+	}
+	if !state.captured {
+		return fmt.Errorf("fragment %s not found in %s", name, view)
+	}
+	_, err := io.WriteString(w, state.fragmentOutput)
+	return err
+}
+
+// fragmentNames statically finds the fragments a template defines, so htmx requests only select fragments of views
+// which have them.
+func fragmentNames(source string) map[string]bool {
+	if !strings.Contains(source, FragmentHelper) {
+		return nil
+	}
+	program, err := parser.Parse(source)
+	if err != nil {
+		return nil
+	}
+	names := make(map[string]bool)
+	w := &walker{
+		onExpression: func(node *ast.Expression, block *ast.BlockStatement) {
+			if block == nil || node.HelperName() != FragmentHelper || len(node.Params) == 0 {
+				return
+			}
+			if name, ok := node.Params[0].(*ast.StringLiteral); ok {
+				names[name.Value] = true
+			}
+		},
+	}
+	w.walk(program)
+	return names
+}
@@ -0,0 +1,203 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+)
+
+func newFragmentRenderer(htmx bool) *handlebars.HandlebarsRenderer {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "search",
+		TemplateData: `<h1>{{title}}</h1>{{#with query}}{{#define-fragment "results"}}<ul>{{#each items}}<li>{{this}}</li>{{/each}}</ul>{{/define-fragment}}{{/with}}{{> pager}}`,
+	})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "plain", TemplateData: "<p>{{title}}</p>"})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "field", TemplateData: `<p>{{fragment}}</p>{{#define-fragment "copy"}}<b>{{fragment}}</b>{{/define-fragment}}`})
+	partials := NewMockTemplateGatherer()
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "pager", TemplateData: "<nav>{{page}}</nav>"})
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "content", TemplateData: "<aside>unrelated</aside>"})
+	layouts := NewMockTemplateGatherer()
+	layouts.AddTemplate(echorend.RawTemplateData{TemplateName: "main", TemplateData: "<main>{{{@body}}}</main>"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    views,
+		PartialGatherer: partials,
+		LayoutGatherer:  layouts,
+		DefaultLayout:   "main",
+		HTMX:            htmx,
+	})
+	renderer.MustSetup()
+	return renderer
+}
+
+func searchData() map[string]interface{} {
+	return map[string]interface{}{
+		"title": "Search",
+		"page":  2,
+		"query": map[string]interface{}{"items": []string{"a", "b"}},
+	}
+}
+
+func htmxContext(target string) echo.Context {
+	req := httptest.NewRequest("GET", "/search", nil)
+	req.Header.Set(handlebars.HXRequestHeader, "true")
+	req.Header.Set(handlebars.HXTargetHeader, target)
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func TestHandlebarsRendererFragment_FullRender_RendersFragmentInPlace(t *testing.T) {
+	renderer := newFragmentRenderer(false)
+
+	got, err := renderToString("search", searchData(), renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got != "<main><h1>Search</h1><ul><li>a</li><li>b</li></ul><nav>2</nav></main>" {
+		t.Errorf("Unexpected render %q", got)
+	}
+}
+
+func TestHandlebarsRendererFragment_Selector_RendersOnlyFragmentWithoutLayout(t *testing.T) {
+	renderer := newFragmentRenderer(false)
+
+	got, err := renderToString("search#results", searchData(), renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got != "<ul><li>a</li><li>b</li></ul>" {
+		t.Errorf("Unexpected render %q", got)
+	}
+}
+
+func TestHandlebarsRendererFragment_SelectorNamingPartial_RendersPartialWithViewData(t *testing.T) {
+	renderer := newFragmentRenderer(false)
+
+	got, err := renderToString("search#pager", searchData(), renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got != "<nav>2</nav>" {
+		t.Errorf("Unexpected render %q", got)
+	}
+}
+
+func TestHandlebarsRendererFragment_UnknownFragment_ReturnsError(t *testing.T) {
+	renderer := newFragmentRenderer(false)
+
+	err := renderer.Render(new(bytes.Buffer), "search#missing", searchData(), nil)
+
+	if err == nil || !strings.Contains(err.Error(), "fragment missing not found in search") {
+		t.Errorf("Expected fragment not found, got %v", err)
+	}
+}
+
+func TestHandlebarsRendererFragment_HTMXTarget_RendersTargetedFragment(t *testing.T) {
+	renderer := newFragmentRenderer(true)
+
+	fragment, err := renderToStringWithContext("search", searchData(), renderer, htmxContext("results"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	partial, err := renderToStringWithContext("search", searchData(), renderer, htmxContext("pager"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	page, err := renderToStringWithContext("search", searchData(), renderer, htmxContext("content"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if fragment != "<ul><li>a</li><li>b</li></ul>" || partial != "<nav>2</nav>" {
+		t.Errorf("Expected the targeted fragment and partial, got %q and %q", fragment, partial)
+	}
+	if page != "<main><h1>Search</h1><ul><li>a</li><li>b</li></ul><nav>2</nav></main>" {
+		t.Errorf("Expected an unknown target to render the full page, got %q", page)
+	}
+}
+
+func TestHandlebarsRendererFragment_HTMXTargetNamingUnreferencedPartial_RendersFullPage(t *testing.T) {
+	renderer := newFragmentRenderer(true)
+
+	got, err := renderToStringWithContext("plain", searchData(), renderer, htmxContext("content"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got != "<main><p>Search</p></main>" {
+		t.Errorf("Expected a partial the view doesn't render to be ignored, got %q", got)
+	}
+}
+
+func TestHandlebarsRendererFragment_HTMXDisabled_IgnoresHeaders(t *testing.T) {
+	renderer := newFragmentRenderer(false)
+
+	got, err := renderToStringWithContext("search", searchData(), renderer, htmxContext("results"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(got, "<main>") {
+		t.Errorf("Expected the full page, got %q", got)
+	}
+}
+
+func TestHandlebarsRendererFragment_CheckRenders_ChecksFieldsInFragments(t *testing.T) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "search", TemplateData: `{{#define-fragment "results"}}{{#each query.items}}{{this}}{{/each}}{{titel}}{{/define-fragment}}`})
+	renderer := handlebars.NewHandlebarsRenderer(views, NewMockTemplateGatherer())
+	renderer.MustSetup()
+	handlebars.Register[searchPage](renderer, "search")
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "titel not found") {
+		t.Errorf("Expected only titel reported, got %v", errs)
+	}
+}
+
+type searchQuery struct {
+	Items []string
+}
+
+type searchPage struct {
+	Title string
+	Page  int
+	Query searchQuery
+}
+
+func TestHandlebarsRendererFragment_FieldNamedFragment_RendersAsField(t *testing.T) {
+	renderer := newFragmentRenderer(false)
+	data := map[string]interface{}{"fragment": "F"}
+
+	full, err := renderToString("field", data, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	selected, err := renderToString("field#copy", data, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if full != "<main><p>F</p><b>F</b></main>" || selected != "<b>F</b>" {
+		t.Errorf("Expected the fragment field, got %q and %q", full, selected)
+	}
+}
+
+func TestSplitFragment_Selector_SplitsViewAndFragment(t *testing.T) {
+	view, fragment := handlebars.SplitFragment("billing:invoices#rows")
+	if view != "billing:invoices" || fragment != "rows" {
+		t.Errorf("Unexpected split %q, %q", view, fragment)
+	}
+	view, fragment = handlebars.SplitFragment("index")
+	if view != "index" || fragment != "" {
+		t.Errorf("Unexpected split %q, %q", view, fragment)
+	}
+}
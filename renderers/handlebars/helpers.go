@@ -0,0 +1,112 @@
+package handlebars
+
+import (
+	"fmt"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/aymerick/raymond"
+	"github.com/aymerick/raymond/ast"
+	"github.com/aymerick/raymond/parser"
+	"github.com/labstack/echo/v4"
+)
+
+// builtinHelpers are the helpers raymond registers globally itself.
+var builtinHelpers = map[string]bool{
+	"if":     true,
+	"unless": true,
+	"with":   true,
+	"each":   true,
+	"log":    true,
+	"lookup": true,
+	"equal":  true,
+}
+
+// EchoContext returns the echo.Context of the render a helper is running in, or nil outside of a request,
+// such as in CheckRenders.
+func EchoContext(options *raymond.Options) echo.Context {
+	return stateFromOptions(options).ctx
+}
+
+// ownHelpers returns the helpers the renderer registers itself, which configured helpers can't replace.
+func (r *HandlebarsRenderer) ownHelpers() map[string]interface{} {
+	helpers := make(map[string]interface{}, len(viewHelpers)+2)
+	for name, helper := range viewHelpers {
+		helpers[name] = helper
+	}
+	helpers[FragmentHelper] = fragment
+	if r.config.Catalog != nil {
+		helpers[TranslateHelper] = r.translate
+	}
+	return helpers
+}
+
+// validateHelpers checks the configured helpers can be registered, rather than letting raymond panic at build time.
+func (r *HandlebarsRenderer) validateHelpers() []error {
+	errs := make([]error, 0)
+	for name, helper := range r.config.Helpers {
+		if r.isOwnHelper(name) {
+			errs = append(errs, fmt.Errorf("helper %s is reserved by the renderer", name))
+			continue
+		}
+		if err := tryRegisterHelper(name, helper); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func tryRegisterHelper(name string, helper interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("helper %s: %v", name, r)
+		}
+	}()
+	raymond.MustParse("").RegisterHelper(name, helper)
+	return nil
+}
+
+// checkHelpers statically finds every call to a helper the renderer doesn't have. Only expressions with
+// parameters or hash arguments are certainly helper calls, as {{name}} may just be a field. Helpers registered
+// globally with raymond can't be seen, so they are reported as unknown.
+func (r *HandlebarsRenderer) checkHelpers(set *templateSet) []error {
+	errs := make([]error, 0)
+	for _, tmpl := range set.analysedTemplates() {
+		program, err := parser.Parse(tmpl.raw.TemplateData)
+		if err != nil {
+			continue
+		}
+		w := &walker{
+			onExpression: func(node *ast.Expression, _ *ast.BlockStatement) {
+				name := node.HelperName()
+				if name == "" || (len(node.Params) == 0 && node.Hash == nil) || r.isHelper(name) {
+					return
+				}
+				errs = append(errs, &echorend.TemplateError{
+					TemplateName: tmpl.raw.TemplateName,
+					Source:       tmpl.raw.Source,
+					Line:         node.Line,
+					Err:          fmt.Errorf("helper %s not found", name),
+				})
+			},
+		}
+		w.walk(program)
+	}
+	return errs
+}
+
+func (r *HandlebarsRenderer) isHelper(name string) bool {
+	if builtinHelpers[name] {
+		return true
+	}
+	if r.isOwnHelper(name) {
+		return true
+	}
+	_, ok := r.config.Helpers[name]
+	return ok
+}
+
+func (r *HandlebarsRenderer) isOwnHelper(name string) bool {
+	_, own := r.helpers[name]
+	_, layout := layoutHelpers[name]
+	return own || layout
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Eq reports whether two values are equal. Numbers are compared by value whatever their type, so an int from a
+// template literal equals a float64 from JSON data. Usually used as a sub-expression.
+//
+//	{{#if (cmpEq status "active")}}...{{/if}}
+func Eq(a, b interface{}) bool {
+	if x, ok := numericValue(a); ok {
+		if y, ok := numericValue(b); ok {
+			return x == y
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Ne reports whether two values are not equal, the inverse of Eq.
+func Ne(a, b interface{}) bool {
+	return !Eq(a, b)
+}
+
+// Lt reports whether a is less than b. Numbers compare by value and strings lexically, and any other
+// combination is false.
+func Lt(a, b interface{}) bool {
+	c, ok := compare(a, b)
+	return ok && c < 0
+}
+
+// Lte reports whether a is less than or equal to b.
+func Lte(a, b interface{}) bool {
+	c, ok := compare(a, b)
+	return ok && c <= 0
+}
+
+// Gt reports whether a is greater than b.
+func Gt(a, b interface{}) bool {
+	c, ok := compare(a, b)
+	return ok && c > 0
+}
+
+// Gte reports whether a is greater than or equal to b.
+func Gte(a, b interface{}) bool {
+	c, ok := compare(a, b)
+	return ok && c >= 0
+}
+
+func compare(a, b interface{}) (int, bool) {
+	if x, ok := numericValue(a); ok {
+		y, ok := numericValue(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
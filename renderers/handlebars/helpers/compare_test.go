@@ -0,0 +1,60 @@
+package helpers_test
+
+import (
+	"testing"
+
+	"github.com/BlindGarret/echorend/renderers/handlebars/helpers"
+)
+
+func TestEq_Values_ComparesByValue(t *testing.T) {
+	cases := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{1, 1.0, true},
+		{int64(2), uint8(2), true},
+		{"a", "a", true},
+		{"1", 1, false},
+		{nil, nil, true},
+		{[]string{"a"}, []string{"a"}, true},
+		{true, false, false},
+	}
+	for _, c := range cases {
+		if got := helpers.Eq(c.a, c.b); got != c.want {
+			t.Errorf("Eq(%#v, %#v): expected %v, got %v", c.a, c.b, c.want, got)
+		}
+		if got := helpers.Ne(c.a, c.b); got == c.want {
+			t.Errorf("Ne(%#v, %#v): expected %v, got %v", c.a, c.b, !c.want, got)
+		}
+	}
+}
+
+func TestComparisons_Values_OrderNumbersAndStrings(t *testing.T) {
+	cases := []struct {
+		a, b             interface{}
+		lt, lte, gt, gte bool
+	}{
+		{1, 2.5, true, true, false, false},
+		{3, 3.0, false, true, false, true},
+		{"b", "a", false, false, true, true},
+		{"1", 2, false, false, false, false},
+		{nil, 1, false, false, false, false},
+	}
+	for _, c := range cases {
+		if helpers.Lt(c.a, c.b) != c.lt || helpers.Lte(c.a, c.b) != c.lte ||
+			helpers.Gt(c.a, c.b) != c.gt || helpers.Gte(c.a, c.b) != c.gte {
+			t.Errorf("Unexpected comparison of %#v and %#v", c.a, c.b)
+		}
+	}
+}
+
+func TestEq_InTemplate_WorksAsSubExpression(t *testing.T) {
+	out := render(t, `{{#if (cmpEq status "active")}}on{{else}}off{{/if}}{{#if (cmpGte count 10)}}!{{/if}}`, map[string]interface{}{
+		"status": "active",
+		"count":  10.0,
+	})
+
+	if out != "on!" {
+		t.Errorf("Expected on!, got %s", out)
+	}
+}
@@ -0,0 +1,141 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymerick/raymond"
+)
+
+// DefaultDateLayout is used by fmtDate when no layout is given.
+const DefaultDateLayout = "2006-01-02"
+
+// currencySymbols maps ISO 4217 codes to the symbol fmtCurrency prefixes amounts with.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"CAD": "CA$",
+	"AUD": "A$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "CN¥",
+	"INR": "₹",
+}
+
+// currencyDecimals holds the codes that don't use two decimal places.
+var currencyDecimals = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// FormatDate formats a time.Time, *time.Time, RFC 3339 string or unix timestamp with a Go time layout.
+// An empty layout uses DefaultDateLayout, and a zero or missing date renders as nothing.
+//
+//	{{fmtDate createdAt "Jan 2, 2006"}}
+func FormatDate(date interface{}, layout string) string {
+	if layout == "" {
+		layout = DefaultDateLayout
+	}
+	t, ok := toTime(date)
+	if !ok || t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+func toTime(date interface{}) (time.Time, bool) {
+	switch d := date.(type) {
+	case time.Time:
+		return d, true
+	case *time.Time:
+		if d == nil {
+			return time.Time{}, false
+		}
+		return *d, true
+	case string:
+		t, err := time.Parse(time.RFC3339, d)
+		if err != nil {
+			panic(fmt.Errorf("fmtDate: %w", err))
+		}
+		return t, true
+	}
+	if f, ok := numericValue(date); ok {
+		return time.Unix(int64(f), 0).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// FormatNumber formats a number with thousands separators. The decimals hash argument sets the number of
+// decimal places, defaulting to none.
+//
+//	{{fmtNumber visitors}} {{fmtNumber ratio decimals=2}}
+func FormatNumber(value interface{}, options *raymond.Options) string {
+	decimals := 0
+	if d := options.HashProp("decimals"); d != nil {
+		decimals = toInt("decimals", d)
+	}
+	f, ok := toFloat(value)
+	if !ok {
+		return ""
+	}
+	return formatFloat(f, decimals)
+}
+
+// FormatCurrency formats an amount with the symbol and decimal places of an ISO 4217 currency code.
+// Unknown codes are written before the amount, and the decimals hash argument overrides the decimal places.
+//
+//	{{fmtCurrency total "USD"}} renders $1,234.50
+func FormatCurrency(value interface{}, currency string, options *raymond.Options) string {
+	f, ok := toFloat(value)
+	if !ok {
+		return ""
+	}
+	code := strings.ToUpper(currency)
+	decimals, ok := currencyDecimals[code]
+	if !ok {
+		decimals = 2
+	}
+	if d := options.HashProp("decimals"); d != nil {
+		decimals = toInt("decimals", d)
+	}
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+	amount := formatFloat(f, decimals)
+	if symbol, ok := currencySymbols[code]; ok {
+		return sign + symbol + amount
+	}
+	return sign + code + " " + amount
+}
+
+func formatFloat(f float64, decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(math.Abs(f), 'f', decimals, 64)
+	whole, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, fraction = s[:i], s[i:]
+	}
+
+	var b strings.Builder
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString(fraction)
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package helpers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BlindGarret/echorend/renderers/handlebars/helpers"
+)
+
+func TestFormatDate_Values_FormatsWithLayout(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 14, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		date   interface{}
+		layout string
+		want   string
+	}{
+		{"time", date, "Jan 2, 2006", "Mar 5, 2024"},
+		{"pointer", &date, "15:04", "14:30"},
+		{"string", "2024-03-05T14:30:00Z", "2006/01/02", "2024/03/05"},
+		{"unix", date.Unix(), "2006-01-02 15:04", "2024-03-05 14:30"},
+		{"default layout", date, "", "2024-03-05"},
+		{"zero", time.Time{}, "", ""},
+		{"nil", nil, "", ""},
+	}
+	for _, c := range cases {
+		if got := helpers.FormatDate(c.date, c.layout); got != c.want {
+			t.Errorf("%s: expected %q, got %q", c.name, c.want, got)
+		}
+	}
+}
+
+func TestFormatDate_InTemplate_RendersDate(t *testing.T) {
+	out := render(t, `{{fmtDate created "Monday"}}`, map[string]interface{}{
+		"created": time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+	})
+
+	if out != "Tuesday" {
+		t.Errorf("Expected Tuesday, got %s", out)
+	}
+}
+
+func TestFormatDate_InvalidString_Errors(t *testing.T) {
+	tmpl := `{{fmtDate created ""}}`
+
+	_, err := rawRender(tmpl, map[string]interface{}{"created": "yesterday"})
+
+	if err == nil {
+		t.Errorf("Expected an error for an unparseable date")
+	}
+}
+
+func TestFormatNumber_InTemplate_AddsSeparators(t *testing.T) {
+	cases := []struct {
+		tmpl  string
+		value interface{}
+		want  string
+	}{
+		{"{{fmtNumber v}}", 1234567, "1,234,567"},
+		{"{{fmtNumber v}}", 999, "999"},
+		{"{{fmtNumber v}}", -1234, "-1,234"},
+		{"{{fmtNumber v decimals=2}}", 1234.5, "1,234.50"},
+		{"{{fmtNumber v decimals=1}}", "1000.25", "1,000.2"},
+		{"{{fmtNumber v}}", -0.2, "0"},
+		{"{{fmtNumber v}}", "abc", ""},
+	}
+	for _, c := range cases {
+		if got := render(t, c.tmpl, map[string]interface{}{"v": c.value}); got != c.want {
+			t.Errorf("%s with %v: expected %q, got %q", c.tmpl, c.value, c.want, got)
+		}
+	}
+}
+
+func TestFormatCurrency_InTemplate_UsesCurrencyRules(t *testing.T) {
+	cases := []struct {
+		tmpl  string
+		value interface{}
+		want  string
+	}{
+		{`{{fmtCurrency v "USD"}}`, 1234.5, "$1,234.50"},
+		{`{{fmtCurrency v "eur"}}`, 10, "€10.00"},
+		{`{{fmtCurrency v "JPY"}}`, 1500, "¥1,500"},
+		{`{{fmtCurrency v "USD"}}`, -3.25, "-$3.25"},
+		{`{{fmtCurrency v "CHF"}}`, 2000, "CHF 2,000.00"},
+		{`{{fmtCurrency v "USD" decimals=0}}`, 99.99, "$100"},
+	}
+	for _, c := range cases {
+		if got := render(t, c.tmpl, map[string]interface{}{"v": c.value}); got != c.want {
+			t.Errorf("%s with %v: expected %q, got %q", c.tmpl, c.value, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package helpers is an optional set of Handlebars helpers for common server rendered HTML tasks.
+// Every helper is stateless, so a set can be shared by any number of renderers and concurrent renders.
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// All returns every helper in the package keyed by its template name, ready to pass as
+// HandlebarsRendererConfig.Helpers. A new map is returned on each call so it can be extended safely.
+// Helpers take precedence over data fields in Handlebars, so names are prefixed by group (fmt, cmp and str) to
+// keep fields such as title or default rendering as fields.
+func All() map[string]interface{} {
+	return map[string]interface{}{
+		"fmtDate":      FormatDate,
+		"fmtNumber":    FormatNumber,
+		"fmtCurrency":  FormatCurrency,
+		"cmpEq":        Eq,
+		"cmpNe":        Ne,
+		"cmpLt":        Lt,
+		"cmpLte":       Lte,
+		"cmpGt":        Gt,
+		"cmpGte":       Gte,
+		"fmtJSON":      JSON,
+		"strPluralize": Pluralize,
+		"strDefault":   Default,
+		"strTruncate":  Truncate,
+		"strUpper":     Upper,
+		"strLower":     Lower,
+		"strTitle":     Title,
+	}
+}
+
+// toFloat converts any numeric value, or a string holding one, to a float64.
+func toFloat(value interface{}) (float64, bool) {
+	if s, ok := value.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	return numericValue(value)
+}
+
+// numericValue converts numeric kinds only to a float64, leaving strings alone.
+func numericValue(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// toInt converts a numeric hash or parameter value to an int, as values from JSON data arrive as float64.
+func toInt(name string, value interface{}) int {
+	f, ok := toFloat(value)
+	if !ok {
+		panic(fmt.Errorf("%s must be a number, got %v", name, value))
+	}
+	return int(f)
+}
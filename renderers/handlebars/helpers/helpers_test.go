@@ -0,0 +1,113 @@
+package helpers_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/BlindGarret/echorend/renderers/handlebars/helpers"
+	"github.com/aymerick/raymond"
+)
+
+// render executes source with every helper registered on the template, as a renderer would.
+func render(t *testing.T, source string, ctx interface{}) string {
+	t.Helper()
+	tmpl := raymond.MustParse(source)
+	tmpl.RegisterHelpers(helpers.All())
+	out, err := tmpl.Exec(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error rendering %q, got %v", source, err)
+	}
+	return out
+}
+
+func TestAll_ReturnsNewMapEachCall(t *testing.T) {
+	first := helpers.All()
+	first["cmpEq"] = nil
+
+	second := helpers.All()
+
+	if second["cmpEq"] == nil {
+		t.Errorf("Expected changes to one map not to affect another")
+	}
+}
+
+func TestAll_WithHandlebarsRenderer_PassesCheckRenders(t *testing.T) {
+	renderer := newRenderer("{{#if (cmpGt count 1)}}{{fmtNumber count}} {{strPluralize count \"item\"}}{{/if}}")
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+func TestAll_WithHandlebarsRenderer_FieldsNamedLikeHelpersRenderAsFields(t *testing.T) {
+	renderer := newRenderer(`<ul>{{#each results}}<li>{{title}} {{default}} {{json}} {{upper}} {{lower}} {{eq}}</li>{{/each}}</ul>`)
+	renderer.MustSetup()
+	buf := new(bytes.Buffer)
+	data := map[string]interface{}{"results": []map[string]interface{}{
+		{"title": "First", "default": "yes", "json": "raw", "upper": "up", "lower": "down", "eq": "same"},
+	}}
+
+	err := renderer.Render(buf, "helpers-view", data, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "<ul><li>First yes raw up down same</li></ul>" {
+		t.Errorf("Expected fields to render, got %q", buf.String())
+	}
+}
+
+func TestAll_ConcurrentRenders_RenderIndependently(t *testing.T) {
+	renderer := newRenderer("{{fmtCurrency total \"USD\"}} {{strPluralize count \"box\"}} {{strTruncate (strUpper name) 5}} {{fmtJSON tags}}")
+	renderer.MustSetup()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			buf := new(bytes.Buffer)
+			data := map[string]interface{}{"total": i * 1000, "count": i, "name": "abcdefgh", "tags": []int{i}}
+			if err := renderer.Render(buf, "helpers-view", data, nil); err != nil {
+				errs <- err
+				return
+			}
+			boxes := "boxes"
+			if i == 1 {
+				boxes = "box"
+			}
+			expected := fmt.Sprintf("%s %s AB... [%d]", formatUSD(i*1000), boxes, i)
+			if buf.String() != expected {
+				errs <- fmt.Errorf("expected %q, got %q", expected, buf.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func newRenderer(view string) *handlebars.HandlebarsRenderer {
+	return handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    gatherer{{TemplateName: "helpers-view", TemplateData: view}},
+		PartialGatherer: gatherer{},
+		Helpers:         helpers.All(),
+	})
+}
+
+func formatUSD(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("$%d,%03d.00", n/1000, n%1000)
+	}
+	return fmt.Sprintf("$%d.00", n)
+}
@@ -0,0 +1,13 @@
+package helpers_test
+
+import "github.com/BlindGarret/echorend"
+
+type gatherer []echorend.RawTemplateData
+
+func (g gatherer) MustGather() []echorend.RawTemplateData {
+	return g
+}
+
+func (g gatherer) Gather() ([]echorend.RawTemplateData, error) {
+	return g, nil
+}
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aymerick/raymond"
+)
+
+// DefaultTruncateSuffix is appended by strTruncate when no suffix is given.
+const DefaultTruncateSuffix = "..."
+
+// JSON encodes a value as JSON for inline script data. The output is not escaped by the template, but
+// json.Marshal escapes <, > and & so it can't close a script element.
+//
+//	<script>window.data = {{fmtJSON data}};</script>
+func JSON(value interface{}) raymond.SafeString {
+	b, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Errorf("fmtJSON: %w", err))
+	}
+	return raymond.SafeString(b)
+}
+
+// Pluralize returns the singular word when count is 1, and its plural otherwise. The plural hash argument
+// gives an irregular plural, otherwise the plural is guessed with English rules.
+//
+//	{{count}} {{strPluralize count "item"}} {{strPluralize count "person" plural="people"}}
+func Pluralize(count interface{}, singular string, options *raymond.Options) string {
+	if n, ok := toFloat(count); ok && n == 1 {
+		return singular
+	}
+	if plural, ok := options.HashProp("plural").(string); ok {
+		return plural
+	}
+	return plural(singular)
+}
+
+func plural(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case lower == "":
+		return word
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	}
+	return word + "s"
+}
+
+// Default returns the value unless it is falsy by Handlebars rules, in which case the fallback is returned.
+//
+//	{{strDefault user.nickname "Anonymous"}}
+func Default(value, fallback interface{}) interface{} {
+	if raymond.IsTrue(value) {
+		return value
+	}
+	return fallback
+}
+
+// Truncate shortens a string to at most length characters, including the suffix hash argument which
+// defaults to DefaultTruncateSuffix. Strings that already fit are returned unchanged.
+//
+//	{{strTruncate summary 100}} {{strTruncate title 20 suffix="…"}}
+func Truncate(s string, length interface{}, options *raymond.Options) string {
+	max := toInt("length", length)
+	if max < 0 {
+		max = 0
+	}
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	suffix := DefaultTruncateSuffix
+	if sfx, ok := options.HashProp("suffix").(string); ok {
+		suffix = sfx
+	}
+	keep := max - utf8.RuneCountInString(suffix)
+	if keep <= 0 {
+		return string([]rune(suffix)[:max])
+	}
+	return string([]rune(s)[:keep]) + suffix
+}
+
+// Upper returns the string in upper case.
+func Upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+// Lower returns the string in lower case.
+func Lower(s string) string {
+	return strings.ToLower(s)
+}
+
+// Title upper cases the first letter of every word and leaves the rest of each word as it is.
+func Title(s string) string {
+	var b strings.Builder
+	start := true
+	for _, r := range s {
+		if start {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune(r)
+		}
+		start = unicode.IsSpace(r) || r == '-'
+	}
+	return b.String()
+}
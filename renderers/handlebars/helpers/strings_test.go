@@ -0,0 +1,104 @@
+package helpers_test
+
+import (
+	"testing"
+
+	"github.com/BlindGarret/echorend/renderers/handlebars/helpers"
+	"github.com/aymerick/raymond"
+)
+
+// rawRender renders without failing the test, for checking helper errors.
+func rawRender(source string, ctx interface{}) (string, error) {
+	tmpl, err := raymond.Parse(source)
+	if err != nil {
+		return "", err
+	}
+	tmpl.RegisterHelpers(helpers.All())
+	return tmpl.Exec(ctx)
+}
+
+func TestJSON_InTemplate_EncodesUnescapedButScriptSafe(t *testing.T) {
+	out := render(t, `{{fmtJSON data}}`, map[string]interface{}{
+		"data": map[string]interface{}{"name": "</script>", "n": 1},
+	})
+
+	if out != `{"n":1,"name":"\u003c/script\u003e"}` {
+		t.Errorf("Unexpected JSON %s", out)
+	}
+}
+
+func TestJSON_Unencodable_Errors(t *testing.T) {
+	_, err := rawRender(`{{fmtJSON data}}`, map[string]interface{}{"data": func() {}})
+
+	if err == nil {
+		t.Errorf("Expected an error encoding a func")
+	}
+}
+
+func TestPluralize_InTemplate_PicksForm(t *testing.T) {
+	cases := []struct {
+		tmpl  string
+		count interface{}
+		want  string
+	}{
+		{`{{strPluralize n "item"}}`, 1, "item"},
+		{`{{strPluralize n "item"}}`, 0, "items"},
+		{`{{strPluralize n "box"}}`, 2, "boxes"},
+		{`{{strPluralize n "church"}}`, 2, "churches"},
+		{`{{strPluralize n "city"}}`, 3.0, "cities"},
+		{`{{strPluralize n "day"}}`, 3, "days"},
+		{`{{strPluralize n "person" plural="people"}}`, 4, "people"},
+		{`{{strPluralize n "person" plural="people"}}`, 1, "person"},
+	}
+	for _, c := range cases {
+		if got := render(t, c.tmpl, map[string]interface{}{"n": c.count}); got != c.want {
+			t.Errorf("%s with %v: expected %q, got %q", c.tmpl, c.count, c.want, got)
+		}
+	}
+}
+
+func TestDefault_InTemplate_FallsBackOnFalsyValues(t *testing.T) {
+	out := render(t, `{{strDefault name "Anonymous"}}|{{strDefault missing "Anonymous"}}|{{strDefault empty 0}}`, map[string]interface{}{
+		"name":  "Bob",
+		"empty": "",
+	})
+
+	if out != "Bob|Anonymous|0" {
+		t.Errorf("Unexpected output %s", out)
+	}
+}
+
+func TestTruncate_InTemplate_ShortensByRunes(t *testing.T) {
+	cases := []struct {
+		tmpl string
+		s    string
+		want string
+	}{
+		{`{{strTruncate s 10}}`, "short", "short"},
+		{`{{strTruncate s 8}}`, "hello world", "hello..."},
+		{`{{strTruncate s 4 suffix="…"}}`, "héllo wörld", "hél…"},
+		{`{{strTruncate s 2}}`, "hello", ".."},
+		{`{{strTruncate s 0}}`, "hello", ""},
+	}
+	for _, c := range cases {
+		if got := render(t, c.tmpl, map[string]interface{}{"s": c.s}); got != c.want {
+			t.Errorf("%s with %q: expected %q, got %q", c.tmpl, c.s, c.want, got)
+		}
+	}
+}
+
+func TestTruncate_FloatLength_IsAccepted(t *testing.T) {
+	out := render(t, `{{strTruncate s n}}`, map[string]interface{}{"s": "hello world", "n": 8.0})
+
+	if out != "hello..." {
+		t.Errorf("Expected hello..., got %s", out)
+	}
+}
+
+func TestCase_InTemplate_ChangesCase(t *testing.T) {
+	out := render(t, `{{strUpper s}}|{{strLower s}}|{{strTitle s}}`, map[string]interface{}{"s": "hello wide-world FOO"})
+
+	if out != "HELLO WIDE-WORLD FOO|hello wide-world foo|Hello Wide-World FOO" {
+		t.Errorf("Unexpected output %s", out)
+	}
+}
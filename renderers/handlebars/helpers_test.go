@@ -0,0 +1,141 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/aymerick/raymond"
+	"github.com/labstack/echo/v4"
+)
+
+func newHelperRenderer(view string, helpers map[string]interface{}) *handlebars.HandlebarsRenderer {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "helper-view",
+		TemplateData: view,
+		Source:       "views/helper-view.hbs",
+	})
+	return handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: NewMockTemplateGatherer(),
+		Helpers:         helpers,
+	})
+}
+
+func TestHandlebarsRendererRender_WithHelper_UsesHelper(t *testing.T) {
+	renderer := newHelperRenderer("{{shout name}}", map[string]interface{}{
+		"shout": func(s string) string { return strings.ToUpper(s) },
+	})
+	renderer.MustSetup()
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "helper-view", map[string]string{"name": "bob"}, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "BOB" {
+		t.Errorf("Expected BOB, got %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_SameHelperNameInTwoRenderers_EachUsesItsOwn(t *testing.T) {
+	first := newHelperRenderer("{{greet name}}", map[string]interface{}{
+		"greet": func(s string) string { return "hello " + s },
+	})
+	second := newHelperRenderer("{{greet name}}", map[string]interface{}{
+		"greet": func(s string) string { return "bonjour " + s },
+	})
+	first.MustSetup()
+	second.MustSetup()
+	firstBuf := new(bytes.Buffer)
+	secondBuf := new(bytes.Buffer)
+
+	_ = first.Render(firstBuf, "helper-view", map[string]string{"name": "bob"}, nil)
+	_ = second.Render(secondBuf, "helper-view", map[string]string{"name": "bob"}, nil)
+
+	if firstBuf.String() != "hello bob" || secondBuf.String() != "bonjour bob" {
+		t.Errorf("Expected per-renderer helpers, got %q and %q", firstBuf.String(), secondBuf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_HelperReadsEchoContext_UsesRequest(t *testing.T) {
+	renderer := newHelperRenderer("{{path}}", map[string]interface{}{
+		"path": func(options *raymond.Options) string {
+			return handlebars.EchoContext(options).Request().URL.Path
+		},
+	})
+	renderer.MustSetup()
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/users/1", nil), httptest.NewRecorder())
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "helper-view", nil, c)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "/users/1" {
+		t.Errorf("Expected request path, got %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererSetup_InvalidHelper_ReturnsError(t *testing.T) {
+	renderer := newHelperRenderer("{{name}}", map[string]interface{}{
+		"broken": "not a function",
+	})
+
+	err := renderer.Setup()
+
+	var setupErr *echorend.SetupError
+	if !errors.As(err, &setupErr) {
+		t.Fatalf("Expected SetupError, got %v", err)
+	}
+}
+
+func TestHandlebarsRendererSetup_ReservedHelperName_ReturnsError(t *testing.T) {
+	renderer := newHelperRenderer("{{name}}", map[string]interface{}{
+		handlebars.BlockForHelper: func() string { return "" },
+	})
+
+	err := renderer.Setup()
+
+	if err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("Expected reserved helper error, got %v", err)
+	}
+}
+
+func TestHandlebarsCheckRenders_UnknownHelper_ReturnsError(t *testing.T) {
+	renderer := newHelperRenderer("<p>\n{{#if show}}{{missing-helper name}}{{/if}}</p>", nil)
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(errs[0], &templateErr) {
+		t.Fatalf("Expected TemplateError, got %v", errs[0])
+	}
+	if templateErr.Source != "views/helper-view.hbs" || templateErr.Line != 2 || !strings.Contains(templateErr.Error(), "missing-helper") {
+		t.Errorf("Unexpected error %+v", templateErr)
+	}
+}
+
+func TestHandlebarsCheckRenders_KnownAndBuiltinHelpers_ReturnsNoErrors(t *testing.T) {
+	renderer := newHelperRenderer("{{#each items}}{{#if this}}{{shout (lookup ../names @index)}}{{/if}}{{/each}}{{field}}", map[string]interface{}{
+		"shout": func(s string) string { return strings.ToUpper(s) },
+	})
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
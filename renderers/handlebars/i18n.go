@@ -0,0 +1,49 @@
+package handlebars
+
+import (
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"github.com/labstack/echo/v4"
+)
+
+// TranslateHelper is the name of the translation helper registered when the renderer has a catalog.
+// Hash arguments are interpolated into the message, and count picks its plural form.
+//
+//	{{t "cart.items" count=items.length}}
+const TranslateHelper = "t"
+
+// LocaleDataKey is the private data key the locale of a render is available under in templates, as @locale.
+const LocaleDataKey = "locale"
+
+// locale works out the locale of a render, which is empty when the renderer has no catalog.
+func (r *HandlebarsRenderer) locale(c echo.Context) string {
+	catalog := r.config.Catalog
+	if catalog == nil {
+		return ""
+	}
+	if c == nil {
+		return catalog.DefaultLocale()
+	}
+	return catalog.Match(r.config.Locale(c)...)
+}
+
+func (r *HandlebarsRenderer) translate(key string, options *raymond.Options) string {
+	return r.config.Catalog.Translate(stateFromOptions(options).locale, key, options.Hash())
+}
+
+// localized returns the most specific template for a locale, trying name.fr-CA, then name.fr, then name itself.
+func localized(templates map[string]*compiledTemplate, name, locale string) (*compiledTemplate, bool) {
+	if locale != "" {
+		if tmpl, ok := templates[name+"."+locale]; ok {
+			return tmpl, true
+		}
+		if i := strings.IndexAny(locale, "-_"); i >= 0 {
+			if tmpl, ok := templates[name+"."+locale[:i]]; ok {
+				return tmpl, true
+			}
+		}
+	}
+	tmpl, ok := templates[name]
+	return tmpl, ok
+}
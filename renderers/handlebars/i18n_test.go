@@ -0,0 +1,132 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/i18n"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+)
+
+func newI18nRenderer(resolver i18n.LocaleResolver) *handlebars.HandlebarsRenderer {
+	catalog := i18n.NewCatalog("en")
+	catalog.Add("en", "greeting", i18n.Text("Hello {name}"))
+	catalog.Add("en", "items", i18n.Message{Forms: map[string]string{"one": "{count} item", "other": "{count} items"}})
+	catalog.Add("fr", "greeting", i18n.Text("Bonjour {name}"))
+	catalog.Add("fr", "items", i18n.Message{Forms: map[string]string{"one": "{count} article", "other": "{count} articles"}})
+	catalog.Add("fr-CA", "greeting", i18n.Text("Allo {name}"))
+
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "i18n-view", TemplateData: "{{@locale}}: {{t \"greeting\" name=name}}, {{t \"items\" count=count}}"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "i18n-page", TemplateData: "page"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "i18n-page.fr", TemplateData: "la page"})
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "i18n-layout", TemplateData: "<html>{{{@body}}}</html>"})
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "i18n-layout.fr-CA", TemplateData: "<html lang=\"fr-CA\">{{{@body}}}</html>"})
+	return handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: NewMockTemplateGatherer(),
+		LayoutGatherer:  layoutGatherer,
+		Catalog:         catalog,
+		Locale:          resolver,
+	})
+}
+
+func newLocaleContext(acceptLanguage string) echo.Context {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", acceptLanguage)
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func TestHandlebarsRendererRender_Translate_UsesAcceptLanguage(t *testing.T) {
+	renderer := newI18nRenderer(nil)
+	renderer.MustSetup()
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "i18n-view", map[string]interface{}{"name": "Ana", "count": 0}, newLocaleContext("de, fr;q=0.8"))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "fr: Bonjour Ana, 0 article" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_TranslateWithoutContext_UsesDefaultLocale(t *testing.T) {
+	renderer := newI18nRenderer(nil)
+	renderer.MustSetup()
+	buf := new(bytes.Buffer)
+
+	_ = renderer.Render(buf, "i18n-view", map[string]interface{}{"name": "Ana", "count": 1}, nil)
+
+	if buf.String() != "en: Hello Ana, 1 item" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_CustomResolver_PicksLocale(t *testing.T) {
+	renderer := newI18nRenderer(i18n.Query("lang"))
+	renderer.MustSetup()
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/?lang=fr", nil), httptest.NewRecorder())
+	buf := new(bytes.Buffer)
+
+	_ = renderer.Render(buf, "i18n-view", map[string]interface{}{"name": "Ana", "count": 2}, c)
+
+	if buf.String() != "fr: Bonjour Ana, 2 articles" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_LocaleOverride_TakesPrecedence(t *testing.T) {
+	renderer := newI18nRenderer(nil)
+	renderer.MustSetup()
+	cases := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"fr", "<html>la page</html>"},
+		{"fr-CA", "<html lang=\"fr-CA\">la page</html>"},
+		{"en", "<html>page</html>"},
+	}
+	for _, c := range cases {
+		buf := new(bytes.Buffer)
+		err := renderer.Render(buf, "i18n-page", map[string]interface{}{handlebars.LayoutKey: "i18n-layout"}, newLocaleContext(c.acceptLanguage))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if buf.String() != c.want {
+			t.Errorf("%s: expected %s, got %s", c.acceptLanguage, c.want, buf.String())
+		}
+	}
+}
+
+func TestHandlebarsRendererSetup_HelperNamedT_IsReservedWithCatalog(t *testing.T) {
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    NewMockTemplateGatherer(),
+		PartialGatherer: NewMockTemplateGatherer(),
+		Catalog:         i18n.NewCatalog("en"),
+		Helpers:         map[string]interface{}{"t": strings.ToUpper},
+	})
+
+	err := renderer.Setup()
+
+	if err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("Expected reserved helper error, got %v", err)
+	}
+}
+
+func TestHandlebarsCheckRenders_TranslateWithCatalog_ReturnsNoErrors(t *testing.T) {
+	renderer := newI18nRenderer(nil)
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
@@ -0,0 +1,116 @@
+package handlebars
+
+import (
+	"github.com/aymerick/raymond"
+	"github.com/labstack/echo/v4"
+)
+
+// LayoutKey picks the layout for a single render, overriding DefaultLayout. It is read from the render data
+// when that is a map[string]interface{}, and otherwise from the echo.Context.
+// The value is the layout name, or an empty string or false to render without a layout.
+const LayoutKey = "_layout"
+
+// renderStateKey is the private data key the render state is stored under for the layout helpers.
+const renderStateKey = "_echorend"
+
+// BodyDataKey is the private data key a layout can output the rendered view with, as {{{@body}}}.
+const BodyDataKey = "body"
+
+// BodyHelper outputs the rendered view in a layout, as {{{body}}}. It is only registered on layouts, where it takes
+// precedence over a body field of the data, which is still read there as {{this.body}}.
+const BodyHelper = "body"
+
+// The layout helpers are named with a dash so they can never shadow a field of the render data, which raymond
+// would otherwise resolve to the helper.
+const (
+	ContentForHelper = "content-for"
+	BlockForHelper   = "block-for"
+)
+
+// renderState carries the rendered view body and named content blocks from a view to its layout, and the
+// echo.Context, @request values and locale of the render.
+type renderState struct {
+	ctx     echo.Context
+	request map[string]interface{}
+	locale  string
+	body    string
+	blocks  map[string]string
+
+	// fragment is the name of the fragment being rendered, if any, captured into fragmentOutput.
+	fragment       string
+	fragmentOutput string
+	captured       bool
+}
+
+func newRenderState(c echo.Context) *renderState {
+	return &renderState{
+		ctx:     c,
+		request: map[string]interface{}{},
+		blocks:  make(map[string]string),
+	}
+}
+
+func (s *renderState) frame() *raymond.DataFrame {
+	frame := raymond.NewDataFrame()
+	frame.Set(renderStateKey, s)
+	frame.Set(BodyDataKey, s.body)
+	frame.Set(RequestDataKey, s.request)
+	frame.Set(LocaleDataKey, s.locale)
+	return frame
+}
+
+func stateFromOptions(options *raymond.Options) *renderState {
+	if state, ok := options.Data(renderStateKey).(*renderState); ok {
+		return state
+	}
+	return newRenderState(nil)
+}
+
+// viewHelpers are registered on views and partials, so they can fill the blocks of their layout.
+//
+//	{{#content-for "name"}}...{{/content-for}}   fills the named block
+var viewHelpers = map[string]interface{}{
+	ContentForHelper: func(name string, options *raymond.Options) string {
+		stateFromOptions(options).blocks[name] = options.Fn()
+		return ""
+	},
+}
+
+// layoutHelpers are registered on layouts only.
+//
+//	{{{body}}}                               outputs the rendered view, as {{{@body}}} does
+//	{{#block-for "name"}}...{{/block-for}}   outputs the named block or its own default content
+var layoutHelpers = map[string]interface{}{
+	BodyHelper: func(options *raymond.Options) raymond.SafeString {
+		return raymond.SafeString(stateFromOptions(options).body)
+	},
+	BlockForHelper: func(name string, options *raymond.Options) raymond.SafeString {
+		if content, ok := stateFromOptions(options).blocks[name]; ok {
+			return raymond.SafeString(content)
+		}
+		return raymond.SafeString(options.Fn())
+	},
+}
+
+// layoutName works out which layout a render should use, preferring the render data, then the echo.Context,
+// then the configured default.
+func (r *HandlebarsRenderer) layoutName(data interface{}, c echo.Context) string {
+	if m, ok := data.(map[string]interface{}); ok {
+		if value, ok := m[LayoutKey]; ok {
+			return layoutValue(value)
+		}
+	}
+	if c != nil {
+		if value := c.Get(LayoutKey); value != nil {
+			return layoutValue(value)
+		}
+	}
+	return r.config.DefaultLayout
+}
+
+func layoutValue(value interface{}) string {
+	if name, ok := value.(string); ok {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+)
+
+func newLayoutRenderer(defaultLayout string) *handlebars.HandlebarsRenderer {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "layout-view",
+		TemplateData: "<p>{{Title}}</p>",
+	})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "layout-blocks-view",
+		TemplateData: "{{#content-for \"title\"}}Custom {{Title}}{{/content-for}}<p>body</p>",
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "layout-partial",
+		TemplateData: "<span>partial</span>",
+	})
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "main",
+		TemplateData: "<html><title>{{#block-for \"title\"}}Default{{/block-for}}</title><body>{{{body}}}</body></html>",
+	})
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "bare",
+		TemplateData: "<main>{{{@body}}}</main>",
+	})
+
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		LayoutGatherer:  layoutGatherer,
+		DefaultLayout:   defaultLayout,
+	})
+	renderer.MustSetup()
+	return renderer
+}
+
+func TestHandlebarsRendererRender_DefaultLayout_WrapsView(t *testing.T) {
+	renderer := newLayoutRenderer("main")
+
+	out, err := renderToString("layout-view", map[string]interface{}{"Title": "Hi"}, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "<html><title>Default</title><body><p>Hi</p></body></html>"
+	if out != expected {
+		t.Errorf("Expected %s, got %s", expected, out)
+	}
+}
+
+func TestHandlebarsRendererRender_NoDefaultLayout_RendersBareView(t *testing.T) {
+	renderer := newLayoutRenderer("")
+
+	out, err := renderToString("layout-view", map[string]interface{}{"Title": "Hi"}, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<p>Hi</p>" {
+		t.Errorf("Expected bare view, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererRender_LayoutKeyInData_UsesChosenLayout(t *testing.T) {
+	renderer := newLayoutRenderer("main")
+
+	out, err := renderToString("layout-view", map[string]interface{}{"Title": "Hi", handlebars.LayoutKey: "bare"}, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<main><p>Hi</p></main>" {
+		t.Errorf("Expected bare layout, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererRender_LayoutKeyFalse_RendersWithoutLayout(t *testing.T) {
+	renderer := newLayoutRenderer("main")
+
+	out, err := renderToString("layout-view", map[string]interface{}{"Title": "Hi", handlebars.LayoutKey: false}, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<p>Hi</p>" {
+		t.Errorf("Expected no layout, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererRender_LayoutKeyInContext_UsesChosenLayout(t *testing.T) {
+	renderer := newLayoutRenderer("main")
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	c.Set(handlebars.LayoutKey, "bare")
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "layout-view", map[string]interface{}{"Title": "Hi"}, c)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "<main><p>Hi</p></main>" {
+		t.Errorf("Expected bare layout, got %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_ViewFillsBlock_LayoutUsesViewContent(t *testing.T) {
+	renderer := newLayoutRenderer("main")
+
+	out, err := renderToString("layout-blocks-view", map[string]interface{}{"Title": "Hi"}, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "<html><title>Custom Hi</title><body><p>body</p></body></html>"
+	if out != expected {
+		t.Errorf("Expected %s, got %s", expected, out)
+	}
+}
+
+func TestHandlebarsRendererRender_PartialAsView_IsNotWrappedInLayout(t *testing.T) {
+	renderer := newLayoutRenderer("main")
+
+	out, err := renderToString("layout-partial", nil, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<span>partial</span>" {
+		t.Errorf("Expected bare partial, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererRender_UnknownLayout_Errors(t *testing.T) {
+	renderer := newLayoutRenderer("missing")
+
+	_, err := renderToString("layout-view", nil, renderer)
+
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestHandlebarsRendererCheckRenders_WithLayouts_ReturnsNoErrors(t *testing.T) {
+	renderer := newLayoutRenderer("main")
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+func TestHandlebarsRendererRender_FieldsNamedLikeLayoutHelpers_RenderAsFieldsOutsideLayouts(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "fields-view",
+		TemplateData: "<p>{{body}}</p><p>{{content}}</p><p>{{block}}</p>",
+	})
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "fields-layout",
+		TemplateData: "<main>{{this.body}}|{{content}}|{{{@body}}}</main>",
+	})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:   viewGatherer,
+		LayoutGatherer: layoutGatherer,
+	})
+	renderer.MustSetup()
+	data := map[string]interface{}{"body": "HELLO", "content": "C", "block": "B"}
+
+	bare, err := renderToString("fields-view", data, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data[handlebars.LayoutKey] = "fields-layout"
+	wrapped, err := renderToString("fields-view", data, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if bare != "<p>HELLO</p><p>C</p><p>B</p>" {
+		t.Errorf("Expected the data fields, got %s", bare)
+	}
+	if wrapped != "<main>HELLO|C|<p>HELLO</p><p>C</p><p>B</p></main>" {
+		t.Errorf("Expected the data fields in the layout, with body read through this, got %s", wrapped)
+	}
+}
@@ -1,8 +1,14 @@
 package handlebars_test
 
-import "github.com/BlindGarret/echorend"
+import (
+	"sync"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/externals"
+)
 
 type MockTemplateGatherer struct {
+	mutex     sync.Mutex
 	templates []echorend.RawTemplateData
 	err       error
 }
@@ -14,20 +20,114 @@ func NewMockTemplateGatherer() *MockTemplateGatherer {
 }
 
 func (m *MockTemplateGatherer) MustGather() []echorend.RawTemplateData {
-	if m.err != nil {
-		panic(m.err)
+	templates, err := m.Gather()
+	if err != nil {
+		panic(err)
 	}
-	return m.templates
+	return templates
 }
 
 func (m *MockTemplateGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	return m.templates, m.err
 }
 
 func (m *MockTemplateGatherer) AddTemplate(template echorend.RawTemplateData) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.templates = append(m.templates, template)
 }
 
 func (m *MockTemplateGatherer) SetError(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.err = err
 }
+
+func (m *MockTemplateGatherer) SetTemplates(templates ...echorend.RawTemplateData) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.templates = templates
+}
+
+type MockWatchableGatherer struct {
+	*MockTemplateGatherer
+	dirs []string
+}
+
+func NewMockWatchableGatherer(dirs ...string) *MockWatchableGatherer {
+	return &MockWatchableGatherer{
+		MockTemplateGatherer: NewMockTemplateGatherer(),
+		dirs:                 dirs,
+	}
+}
+
+func (m *MockWatchableGatherer) WatchDirs() []string {
+	return m.dirs
+}
+
+// MockFileWatcher is a FileWatcher whose events are sent by the test.
+type MockFileWatcher struct {
+	watchedDirs []string
+	events      chan string
+	errors      chan error
+	closed      bool
+}
+
+func NewMockFileWatcher() *MockFileWatcher {
+	return &MockFileWatcher{
+		events: make(chan string),
+		errors: make(chan error),
+	}
+}
+
+func (m *MockFileWatcher) Watch(dirs []string) (externals.FileWatch, error) {
+	m.watchedDirs = dirs
+	return m, nil
+}
+
+func (m *MockFileWatcher) Events() <-chan string {
+	return m.events
+}
+
+func (m *MockFileWatcher) Errors() <-chan error {
+	return m.errors
+}
+
+func (m *MockFileWatcher) Close() error {
+	m.closed = true
+	close(m.events)
+	return nil
+}
+
+func (m *MockFileWatcher) Change(path string) {
+	m.events <- path
+}
+
+// combinedGatherer returns the templates of several gatherers, for tests needing namespaced and plain templates.
+type combinedGatherer []echorend.RawTemplateGatherer
+
+func combined(gatherers ...echorend.RawTemplateGatherer) combinedGatherer {
+	return gatherers
+}
+
+func (g combinedGatherer) MustGather() []echorend.RawTemplateData {
+	templates, err := g.Gather()
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+func (g combinedGatherer) Gather() ([]echorend.RawTemplateData, error) {
+	templates := make([]echorend.RawTemplateData, 0)
+	for _, gatherer := range g {
+		gathered, err := gatherer.Gather()
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, gathered...)
+	}
+	return templates, nil
+}
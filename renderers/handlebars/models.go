@@ -0,0 +1,299 @@
+package handlebars
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/aymerick/raymond/ast"
+	"github.com/aymerick/raymond/parser"
+	"github.com/labstack/echo/v4"
+)
+
+// View is a view whose data is a T. CheckRenders checks every field the view uses exists on T.
+type View[T any] struct {
+	renderer *HandlebarsRenderer
+	name     string
+}
+
+// Register declares the type of data a view is rendered with, returning a typed handle to render it through.
+// It can be called before or after Setup.
+func Register[T any](r *HandlebarsRenderer, name string) View[T] {
+	name = echorend.CanonicalName(name)
+	r.modelsMutex.Lock()
+	defer r.modelsMutex.Unlock()
+	r.models[name] = reflect.TypeOf((*T)(nil)).Elem()
+	return View[T]{renderer: r, name: name}
+}
+
+// Name returns the name of the view.
+func (v View[T]) Name() string {
+	return v.name
+}
+
+// Render renders the view with its data to the IO writer.
+func (v View[T]) Render(w io.Writer, data T, c echo.Context) error {
+	return v.renderer.Render(w, v.name, data, c)
+}
+
+// Respond renders the view as the response to a request through echo's renderer, as c.Render does.
+func (v View[T]) Respond(c echo.Context, code int, data T) error {
+	return c.Render(code, v.name, data)
+}
+
+// checkModels checks the field paths of every registered view against the type of its data.
+func (r *HandlebarsRenderer) checkModels(set *templateSet) []error {
+	r.modelsMutex.RLock()
+	defer r.modelsMutex.RUnlock()
+
+	errs := make([]error, 0)
+	for name, model := range r.models {
+		tmpl, ok := set.templates[name]
+		if !ok || tmpl.role != roleView {
+			errs = append(errs, fmt.Errorf("view %s registered with %s not found", name, model))
+			continue
+		}
+		program, err := parser.Parse(tmpl.raw.TemplateData)
+		if err != nil {
+			continue
+		}
+		checker := &modelChecker{renderer: r, raw: tmpl.raw}
+		checker.checkProgram(program, []reflect.Type{model}, nil)
+		errs = append(errs, checker.errs...)
+	}
+	return errs
+}
+
+// modelChecker follows the data context through a template the way raymond evaluates it, checking each field path
+// resolves. A nil type in the context stack is data whose type can't be known, such as an interface{} value or
+// the context inside a custom block helper, and is never reported.
+type modelChecker struct {
+	renderer *HandlebarsRenderer
+	raw      echorend.RawTemplateData
+	errs     []error
+}
+
+func (m *modelChecker) checkProgram(program *ast.Program, stack []reflect.Type, blockParams map[string]bool) {
+	for _, statement := range program.Body {
+		switch node := statement.(type) {
+		case *ast.MustacheStatement:
+			m.checkExpression(node.Expression, stack, blockParams)
+		case *ast.BlockStatement:
+			m.checkBlock(node, stack, blockParams)
+		case *ast.PartialStatement:
+			for _, param := range node.Params {
+				m.checkNode(param, stack, blockParams)
+			}
+			m.checkHash(node.Hash, stack, blockParams)
+		}
+	}
+}
+
+func (m *modelChecker) checkBlock(node *ast.BlockStatement, stack []reflect.Type, blockParams map[string]bool) {
+	expr := node.Expression
+	inner := stack
+	switch name := expr.HelperName(); {
+	case name == "if" || name == "unless" || name == FragmentHelper:
+		m.checkExpression(expr, stack, blockParams)
+	case (name == "each" || name == "with") && len(expr.Params) == 1:
+		t := m.checkNode(expr.Params[0], stack, blockParams)
+		if name == "each" {
+			t = elemType(t)
+		}
+		inner = push(stack, t)
+	case len(expr.Params) == 0 && expr.Hash == nil && !m.renderer.isHelper(name):
+		// a section over a field, which iterates lists and otherwise changes the context to the field
+		t := m.checkNode(expr.Path, stack, blockParams)
+		if elem := elemType(t); elem != nil {
+			t = elem
+		}
+		inner = push(stack, t)
+	default:
+		m.checkExpression(expr, stack, blockParams)
+		inner = push(stack, nil)
+	}
+
+	if node.Program != nil {
+		m.checkProgram(node.Program, inner, withBlockParams(blockParams, node.Program.BlockParams))
+	}
+	if node.Inverse != nil {
+		m.checkProgram(node.Inverse, stack, blockParams)
+	}
+}
+
+func (m *modelChecker) checkExpression(expr *ast.Expression, stack []reflect.Type, blockParams map[string]bool) reflect.Type {
+	if len(expr.Params) > 0 || expr.Hash != nil {
+		for _, param := range expr.Params {
+			m.checkNode(param, stack, blockParams)
+		}
+		m.checkHash(expr.Hash, stack, blockParams)
+		return nil
+	}
+	if name := expr.HelperName(); name != "" && m.renderer.isHelper(name) {
+		return nil
+	}
+	return m.checkNode(expr.Path, stack, blockParams)
+}
+
+func (m *modelChecker) checkHash(hash *ast.Hash, stack []reflect.Type, blockParams map[string]bool) {
+	if hash == nil {
+		return
+	}
+	for _, pair := range hash.Pairs {
+		m.checkNode(pair.Val, stack, blockParams)
+	}
+}
+
+// checkNode checks a parameter, returning its type when it is known.
+func (m *modelChecker) checkNode(node ast.Node, stack []reflect.Type, blockParams map[string]bool) reflect.Type {
+	switch n := node.(type) {
+	case *ast.PathExpression:
+		return m.checkPath(n, stack, blockParams)
+	case *ast.SubExpression:
+		return m.checkExpression(n.Expression, stack, blockParams)
+	}
+	return nil
+}
+
+func (m *modelChecker) checkPath(path *ast.PathExpression, stack []reflect.Type, blockParams map[string]bool) reflect.Type {
+	parts := path.Parts
+	if path.Data {
+		if !path.IsDataRoot() {
+			return nil
+		}
+		stack, parts = stack[:1], parts[1:]
+	} else if len(parts) > 0 && blockParams[parts[0]] {
+		return nil
+	}
+
+	index := len(stack) - 1 - path.Depth
+	if index < 0 {
+		return nil
+	}
+	if len(parts) == 0 {
+		return stack[index]
+	}
+
+	// raymond looks for the first part in each enclosing context in turn, unless the path is scoped to one.
+	t, found, known := resolveField(stack[index], parts[0])
+	for i := index - 1; !found && known && i >= 0 && path.Depth == 0 && !path.Scoped; i-- {
+		t, found, known = resolveField(stack[i], parts[0])
+	}
+	for i := 1; found && known && i < len(parts); i++ {
+		t, found, known = resolveField(t, parts[i])
+	}
+	if !known {
+		return nil
+	}
+	if !found {
+		m.errs = append(m.errs, &echorend.TemplateError{
+			TemplateName: m.raw.TemplateName,
+			Source:       m.raw.Source,
+			Line:         path.Line,
+			Err:          fmt.Errorf("%s not found on %s", path.Original, stack[index]),
+		})
+		return nil
+	}
+	return t
+}
+
+// resolveField finds the type of a field, method or map value by name as raymond does. known is false when the
+// type can't be checked.
+func resolveField(t reflect.Type, name string) (result reflect.Type, found bool, known bool) {
+	if t == nil {
+		return nil, false, false
+	}
+	if len(name) >= 2 && name[0] == '[' && name[len(name)-1] == ']' {
+		name = name[1 : len(name)-1]
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface {
+		return nil, false, false
+	}
+
+	// raymond capitalizes names the same way to find exported fields and methods.
+	exported := strings.Title(name)
+	for _, candidate := range []string{name, exported} {
+		if method, ok := reflect.PtrTo(t).MethodByName(candidate); ok {
+			return funcResult(method.Type), true, true
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if field, ok := t.FieldByName(exported); ok && field.PkgPath == "" {
+			return funcResult(field.Type), true, true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).Tag.Get("handlebars") == name {
+				return funcResult(t.Field(i).Type), true, true
+			}
+		}
+		return nil, false, true
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, false, false
+		}
+		return funcResult(t.Elem()), true, true
+	case reflect.Slice, reflect.Array:
+		if _, err := strconv.Atoi(name); err == nil {
+			return funcResult(t.Elem()), true, true
+		}
+		return nil, false, true
+	}
+	return nil, false, true
+}
+
+// funcResult returns what raymond finds for a value of type t, which for a function is the value it returns.
+func funcResult(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Func {
+		if t.NumOut() == 0 {
+			return nil
+		}
+		t = t.Out(0)
+	}
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	return t
+}
+
+// elemType returns the type each iteration of a list or map sees, or nil if t can't be iterated or isn't known.
+func elemType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return funcResult(t.Elem())
+	}
+	return nil
+}
+
+func push(stack []reflect.Type, t reflect.Type) []reflect.Type {
+	pushed := make([]reflect.Type, len(stack), len(stack)+1)
+	copy(pushed, stack)
+	return append(pushed, t)
+}
+
+func withBlockParams(blockParams map[string]bool, names []string) map[string]bool {
+	if len(names) == 0 {
+		return blockParams
+	}
+	scoped := make(map[string]bool, len(blockParams)+len(names))
+	for name := range blockParams {
+		scoped[name] = true
+	}
+	for _, name := range names {
+		scoped[name] = true
+	}
+	return scoped
+}
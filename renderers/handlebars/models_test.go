@@ -0,0 +1,130 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+)
+
+type modelUser struct {
+	Name    string
+	Email   string `handlebars:"mail"`
+	Profile *modelProfile
+}
+
+func (u modelUser) Initials() string {
+	return strings.ToUpper(u.Name[:1])
+}
+
+type modelProfile struct {
+	Bio string
+}
+
+type modelPage struct {
+	Title string
+	Users []modelUser
+	Tags  map[string]string
+	Extra interface{}
+}
+
+func newModelRenderer(view string) *handlebars.HandlebarsRenderer {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "model-view", TemplateData: view, Source: "views/model-view.hbs"})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "model-partial", TemplateData: "{{anything}}"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		Helpers:         map[string]interface{}{"shout": strings.ToUpper},
+	})
+	renderer.MustSetup()
+	return renderer
+}
+
+func TestHandlebarsCheckRenders_RegisteredModel_ValidPathsReturnNoErrors(t *testing.T) {
+	renderer := newModelRenderer(`{{title}} {{#each users}}{{name}} {{mail}} {{initials}} {{profile.bio}} {{../title}} {{title}} {{@index}}{{/each}}` +
+		`{{#with users.[0]}}{{name}}{{/with}}{{#if title}}{{shout title}}{{/if}}{{tags.any}}{{extra.whatever}}` +
+		`{{#users}}{{email}}{{/users}}{{#each users as |user|}}{{user.name}}{{/each}}{{> model-partial users}}{{@root.title}}`)
+	handlebars.Register[modelPage](renderer, "model-view")
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+func TestHandlebarsCheckRenders_RegisteredModel_MisspelledPathsReturnErrors(t *testing.T) {
+	renderer := newModelRenderer("{{titel}}\n{{#each users}}{{nmae}} {{profile.boi}}{{/each}}\n{{shout users.[0].emial}}{{@root.nope}}")
+	handlebars.Register[*modelPage](renderer, "model-view")
+
+	errs := renderer.CheckRenders()
+
+	expected := []string{"titel", "nmae", "profile.boi", "users.[0].emial", "@root.nope"}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), errs)
+	}
+	for _, path := range expected {
+		found := false
+		for _, err := range errs {
+			found = found || strings.Contains(err.Error(), path+" not found")
+		}
+		if !found {
+			t.Errorf("Expected an error for %s, got %v", path, errs)
+		}
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(errs[0], &templateErr) || templateErr.Source != "views/model-view.hbs" || templateErr.Line == 0 {
+		t.Errorf("Expected a TemplateError with a location, got %v", errs[0])
+	}
+}
+
+func TestHandlebarsCheckRenders_RegisteredMissingView_ReturnsError(t *testing.T) {
+	renderer := newModelRenderer("{{title}}")
+	handlebars.Register[modelPage](renderer, "no-such-view")
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "no-such-view") {
+		t.Errorf("Expected an error for the missing view, got %v", errs)
+	}
+}
+
+func TestViewRender_TypedData_RendersView(t *testing.T) {
+	renderer := newModelRenderer("{{title}}: {{#each users}}{{name}}{{/each}}")
+	view := handlebars.Register[modelPage](renderer, "model-view")
+	buf := new(bytes.Buffer)
+
+	err := view.Render(buf, modelPage{Title: "Users", Users: []modelUser{{Name: "ana"}}}, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "Users: ana" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestViewRespond_EchoContext_WritesResponse(t *testing.T) {
+	renderer := newModelRenderer("{{title}}")
+	view := handlebars.Register[modelPage](renderer, "model-view")
+	e := echo.New()
+	e.Renderer = renderer
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest("GET", "/", nil), rec)
+
+	err := view.Respond(c, 201, modelPage{Title: "Created"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rec.Code != 201 || rec.Body.String() != "Created" {
+		t.Errorf("Unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,54 @@
+package handlebars
+
+import (
+	"sort"
+
+	"github.com/BlindGarret/echorend"
+)
+
+// resolvePartial returns the partial name a reference in a template of the given namespace resolves to.
+// Qualified names are used as written, while unqualified names prefer a partial in the template's own namespace.
+func resolvePartial(namespace, name string, has func(string) bool) string {
+	name = echorend.CanonicalName(name)
+	if referenced, _ := echorend.SplitName(name); referenced != "" || namespace == "" {
+		return name
+	}
+	if qualified := echorend.QualifiedName(namespace, name); has(qualified) {
+		return qualified
+	}
+	return name
+}
+
+// canonicalNames returns the gathered templates with every name in the billing:invoice/show form.
+func canonicalNames(gathered map[templateRole][]echorend.RawTemplateData) map[templateRole][]echorend.RawTemplateData {
+	canonical := make(map[templateRole][]echorend.RawTemplateData)
+	for role, templates := range gathered {
+		canonical[role] = make([]echorend.RawTemplateData, 0, len(templates))
+		for _, data := range templates {
+			data.TemplateName = echorend.CanonicalName(data.TemplateName)
+			canonical[role] = append(canonical[role], data)
+		}
+	}
+	return canonical
+}
+
+// qualifyPartials rewrites a template's partial references to the names they resolve to. raymond looks partials up
+// on the template being rendered, not the one containing the reference, so a partial nested in a partial from
+// another namespace would otherwise resolve in the wrong namespace.
+func qualifyPartials(data echorend.RawTemplateData, references []partialReference, has func(string) bool) string {
+	namespace, _ := echorend.SplitName(data.TemplateName)
+	source := data.TemplateData
+	sorted := append([]partialReference(nil), references...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].pos > sorted[j].pos
+	})
+	for _, reference := range sorted {
+		resolved := resolvePartial(namespace, reference.name, has)
+		end := reference.pos + len(reference.name)
+		if resolved == reference.name || end > len(source) || source[reference.pos:end] != reference.name {
+			continue
+		}
+		source = source[:reference.pos] + resolved + source[end:]
+	}
+	return source
+}
@@ -0,0 +1,132 @@
+package handlebars_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/gatherers/namespace"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+)
+
+func newNamespacedRenderer() (*handlebars.HandlebarsRenderer, *MockTemplateGatherer) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "cart", TemplateData: "{{> line}}|{{> billing:summary}}"})
+	billingViews := NewMockTemplateGatherer()
+	billingViews.AddTemplate(echorend.RawTemplateData{TemplateName: "invoice/show", TemplateData: "{{> line}}|{{> header}}"})
+	partials := NewMockTemplateGatherer()
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "line", TemplateData: "app line"})
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "header", TemplateData: "app header"})
+	billingPartials := NewMockTemplateGatherer()
+	billingPartials.SetTemplates(
+		echorend.RawTemplateData{TemplateName: "line", TemplateData: "billing line"},
+		echorend.RawTemplateData{TemplateName: "summary", TemplateData: "summary of {{> line}}"},
+	)
+
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    combined(views, namespace.NewNamespacedGatherer("billing", billingViews)),
+		PartialGatherer: combined(partials, namespace.NewNamespacedGatherer("billing", billingPartials)),
+	})
+	return renderer, billingPartials
+}
+
+func TestHandlebarsRendererRender_NamespacedView_ResolvesOwnNamespaceFirst(t *testing.T) {
+	renderer, _ := newNamespacedRenderer()
+	renderer.MustSetup()
+
+	out, err := renderToString("billing:invoice/show", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if out != "billing line|app header" {
+		t.Errorf("Unexpected output %s", out)
+	}
+}
+
+func TestHandlebarsRendererRender_AtForm_ResolvesNamespacedView(t *testing.T) {
+	renderer, _ := newNamespacedRenderer()
+	renderer.MustSetup()
+
+	out, err := renderToString("@billing/invoice/show", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if out != "billing line|app header" {
+		t.Errorf("Unexpected output %s", out)
+	}
+}
+
+func TestHandlebarsRendererRender_AtFormPartialReference_RendersNamespacedPartial(t *testing.T) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "cart", TemplateData: "{{> @billing/row}}|{{> [billing:row]}}"})
+	billingPartials := NewMockTemplateGatherer()
+	billingPartials.AddTemplate(echorend.RawTemplateData{TemplateName: "row", TemplateData: "billing row"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    views,
+		PartialGatherer: namespace.NewNamespacedGatherer("billing", billingPartials),
+	})
+	renderer.MustSetup()
+
+	out, err := renderToString("cart", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if out != "billing row|billing row" {
+		t.Errorf("Unexpected output %s", out)
+	}
+}
+
+func TestHandlebarsRendererRender_NestedPartialFromOtherNamespace_ResolvesInPartialsNamespace(t *testing.T) {
+	renderer, _ := newNamespacedRenderer()
+	renderer.MustSetup()
+
+	out, err := renderToString("cart", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if out != "app line|summary of billing line" {
+		t.Errorf("Unexpected output %s", out)
+	}
+}
+
+func TestHandlebarsRendererReload_PartialRemovedFromNamespace_FallsBackToUnqualified(t *testing.T) {
+	renderer, billingPartials := newNamespacedRenderer()
+	renderer.MustSetup()
+	billingPartials.SetTemplates(echorend.RawTemplateData{TemplateName: "summary", TemplateData: "summary of {{> line}}"})
+
+	if err := renderer.Reload(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out, err := renderToString("billing:invoice/show", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "app line|app header" {
+		t.Errorf("Unexpected output %s", out)
+	}
+}
+
+func TestHandlebarsCheckRenders_Namespaces_ResolvesReferencesByNamespace(t *testing.T) {
+	renderer, billingPartials := newNamespacedRenderer()
+	billingPartials.AddTemplate(echorend.RawTemplateData{TemplateName: "broken", TemplateData: "{{> @billing/missing}}"})
+	renderer.MustSetup()
+
+	errs := renderer.CheckRenders()
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	var templateErr *echorend.TemplateError
+	if !errors.As(errs[0], &templateErr) || templateErr.TemplateName != "billing:broken" {
+		t.Errorf("Expected the missing partial to be reported against billing:broken, got %v", errs[0])
+	}
+	if unused := renderer.UnusedPartials(); !reflect.DeepEqual(unused, []string{"billing:broken"}) {
+		t.Errorf("Expected only billing:broken unused, got %v", unused)
+	}
+}
@@ -0,0 +1,54 @@
+package handlebars
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/labstack/echo/v4"
+)
+
+// observeBuild tells the observer about a setup or reload, sized by the templates in the set it built.
+func (r *HandlebarsRenderer) observeBuild(operation echorend.Operation, start time.Time, err error) {
+	if r.config.Observer == nil {
+		return
+	}
+	size := 0
+	if err == nil {
+		size = len(r.current().sorted())
+	}
+	r.config.Observer.Observe(echorend.Event{
+		Operation: operation,
+		Start:     start,
+		Duration:  time.Since(start),
+		Size:      size,
+		Err:       err,
+		Context:   context.Background(),
+	})
+}
+
+func requestContext(c echo.Context) context.Context {
+	if c == nil || c.Request() == nil {
+		return context.Background()
+	}
+	return c.Request().Context()
+}
+
+// countingWriter counts the bytes written through it, keeping io.WriteString free of copies when w supports it.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
+
+func (cw *countingWriter) WriteString(s string) (int, error) {
+	n, err := io.WriteString(cw.w, s)
+	cw.n += n
+	return n, err
+}
@@ -0,0 +1,88 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/observers/memory"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+)
+
+func newObservedRenderer() (*handlebars.HandlebarsRenderer, *MockTemplateGatherer, *memory.MemoryObserver) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "index", TemplateData: "<p>{{name}}</p>"})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "broken", TemplateData: "{{> missing}}"})
+	observer := memory.NewMemoryObserver()
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer: views,
+		Observer:     observer,
+	})
+	return renderer, views, observer
+}
+
+func TestHandlebarsRendererObserver_Render_ObservesNameSizeAndContext(t *testing.T) {
+	renderer, _, observer := newObservedRenderer()
+	renderer.MustSetup()
+	req := httptest.NewRequest("GET", "/", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	if err := renderer.Render(new(bytes.Buffer), "index", map[string]string{"name": "World"}, c); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	renders := observer.Operations(echorend.OperationRender)
+	if len(renders) != 1 {
+		t.Fatalf("Expected 1 render, got %d", len(renders))
+	}
+	render := renders[0]
+	if render.Template != "index" || render.Size != len("<p>World</p>") || render.Err != nil {
+		t.Errorf("Unexpected render event %+v", render)
+	}
+	if render.Context != req.Context() {
+		t.Errorf("Expected the request context")
+	}
+	if render.Start.IsZero() || render.Duration <= 0 {
+		t.Errorf("Expected the render timed, got %v for %v", render.Start, render.Duration)
+	}
+}
+
+func TestHandlebarsRendererObserver_FailedRender_ObservesError(t *testing.T) {
+	renderer, _, observer := newObservedRenderer()
+	renderer.MustSetup()
+
+	err := renderer.Render(new(bytes.Buffer), "broken", nil, nil)
+
+	renders := observer.Operations(echorend.OperationRender)
+	if len(renders) != 1 || renders[0].Err != err || err == nil {
+		t.Errorf("Expected the render error observed, got %v", renders)
+	}
+	if renders[0].Context == nil {
+		t.Errorf("Expected a background context outside of a request")
+	}
+}
+
+func TestHandlebarsRendererObserver_SetupAndReload_ObservesTemplateCount(t *testing.T) {
+	renderer, views, observer := newObservedRenderer()
+	renderer.MustSetup()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "about", TemplateData: "about"})
+	if err := renderer.Reload(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "invalid", TemplateData: "{{#if}}"})
+	reloadErr := renderer.Reload()
+
+	setups := observer.Operations(echorend.OperationSetup)
+	if len(setups) != 1 || setups[0].Size != 2 || setups[0].Err != nil {
+		t.Errorf("Expected a setup of 2 templates, got %+v", setups)
+	}
+	reloads := observer.Operations(echorend.OperationReload)
+	if len(reloads) != 2 || reloads[0].Size != 3 {
+		t.Fatalf("Expected 2 reloads, the first of 3 templates, got %+v", reloads)
+	}
+	if reloads[1].Err != reloadErr || reloadErr == nil || reloads[1].Size != 0 {
+		t.Errorf("Expected the failed reload observed with its error, got %+v", reloads[1])
+	}
+}
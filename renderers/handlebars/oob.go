@@ -0,0 +1,77 @@
+package handlebars
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Fragment is one template of a response made of several, see RenderFragments.
+type Fragment struct {
+	// Name is the view, partial or view#fragment selector to render.
+	Name string
+	Data interface{}
+	// Target is the id of the element an out of band fragment updates. It is ignored for the primary fragment.
+	Target string
+	// Swap is the hx-swap-oob strategy of an out of band fragment, such as outerHTML, innerHTML or beforeend,
+	// defaulting to true, which htmx treats as outerHTML.
+	Swap string
+	// Tag is the element out of band fragments are wrapped in, defaulting to div.
+	Tag string
+}
+
+// RenderFragments renders several templates into one htmx response. The first is the primary fragment, rendered as
+// Render would render it. The others are rendered without a layout and wrapped in elements htmx swaps out of band,
+// such as <div id="cart-count" hx-swap-oob="true">...</div>, in order. Every out of band fragment needs a Target.
+func (r *HandlebarsRenderer) RenderFragments(w io.Writer, c echo.Context, fragments ...Fragment) error {
+	for i, fragment := range fragments {
+		if i > 0 && fragment.Target == "" {
+			return fmt.Errorf("out of band fragment %s has no target", fragment.Name)
+		}
+	}
+
+	set := r.current()
+	for i, fragment := range fragments {
+		if i == 0 {
+			if err := r.renderObserved(set, w, r.htmxSelector(set, fragment.Name, c), fragment.Data, c, false); err != nil {
+				return err
+			}
+			continue
+		}
+
+		swap, tag := fragment.Swap, fragment.Tag
+		if swap == "" {
+			swap = "true"
+		}
+		if tag == "" {
+			tag = "div"
+		}
+		if _, err := fmt.Fprintf(w, `<%s id="%s" hx-swap-oob="%s">`, tag, html.EscapeString(fragment.Target), html.EscapeString(swap)); err != nil {
+			return err
+		}
+		if err := r.renderObserved(set, w, fragment.Name, fragment.Data, c, true); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "</%s>", tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fragments responds with several templates rendered by RenderFragments, using the echo instance's renderer,
+// which must be a *HandlebarsRenderer. Nothing is written if any fragment fails to render.
+func Fragments(c echo.Context, code int, fragments ...Fragment) error {
+	r, ok := c.Echo().Renderer.(*HandlebarsRenderer)
+	if !ok {
+		return fmt.Errorf("echo renderer %T is not a *HandlebarsRenderer", c.Echo().Renderer)
+	}
+	buf := new(bytes.Buffer)
+	if err := r.RenderFragments(buf, c, fragments...); err != nil {
+		return err
+	}
+	return c.HTMLBlob(code, buf.Bytes())
+}
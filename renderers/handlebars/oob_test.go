@@ -0,0 +1,154 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+)
+
+func newCartRenderer() *handlebars.HandlebarsRenderer {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "cart/item", TemplateData: `<li>{{name}}</li>`})
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "cart", TemplateData: `<h1>Cart</h1>{{#define-fragment "count"}}{{count}} items{{/define-fragment}}`})
+	partials := NewMockTemplateGatherer()
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "flash", TemplateData: `<p>{{message}}</p>`})
+	layouts := NewMockTemplateGatherer()
+	layouts.AddTemplate(echorend.RawTemplateData{TemplateName: "main", TemplateData: "<main>{{{@body}}}</main>"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    views,
+		PartialGatherer: partials,
+		LayoutGatherer:  layouts,
+		DefaultLayout:   "main",
+	})
+	renderer.MustSetup()
+	return renderer
+}
+
+func cartFragments() []handlebars.Fragment {
+	return []handlebars.Fragment{
+		{Name: "cart/item", Data: map[string]interface{}{"name": "Book", "_layout": ""}},
+		{Name: "cart#count", Data: map[string]interface{}{"count": 3}, Target: "cart-count"},
+		{Name: "flash", Data: map[string]interface{}{"message": "Added"}, Target: "flash", Swap: "beforeend"},
+		{Name: "cart/item", Data: map[string]interface{}{"name": "Pen"}, Target: "items", Swap: "beforeend", Tag: "ul"},
+	}
+}
+
+func TestHandlebarsRendererRenderFragments_OutOfBand_WrapsAllButPrimary(t *testing.T) {
+	renderer := newCartRenderer()
+	buf := new(bytes.Buffer)
+
+	if err := renderer.RenderFragments(buf, nil, cartFragments()...); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `<li>Book</li>` +
+		`<div id="cart-count" hx-swap-oob="true">3 items</div>` +
+		`<div id="flash" hx-swap-oob="beforeend"><p>Added</p></div>` +
+		`<ul id="items" hx-swap-oob="beforeend"><li>Pen</li></ul>`
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestHandlebarsRendererRenderFragments_Primary_RendersInLayout(t *testing.T) {
+	renderer := newCartRenderer()
+	buf := new(bytes.Buffer)
+
+	err := renderer.RenderFragments(buf, nil,
+		handlebars.Fragment{Name: "cart/item", Data: map[string]interface{}{"name": "Book"}},
+		handlebars.Fragment{Name: "cart/item", Data: map[string]interface{}{"name": "Pen"}, Target: "last"},
+	)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != `<main><li>Book</li></main><div id="last" hx-swap-oob="true"><li>Pen</li></div>` {
+		t.Errorf("Expected only the primary in the layout, got %q", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRenderFragments_MissingTarget_WritesNothing(t *testing.T) {
+	renderer := newCartRenderer()
+	buf := new(bytes.Buffer)
+
+	err := renderer.RenderFragments(buf, nil,
+		handlebars.Fragment{Name: "cart/item"},
+		handlebars.Fragment{Name: "flash"},
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "out of band fragment flash has no target") {
+		t.Errorf("Expected missing target error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written, got %q", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRenderFragments_EscapesAttributes(t *testing.T) {
+	renderer := newCartRenderer()
+	buf := new(bytes.Buffer)
+
+	err := renderer.RenderFragments(buf, nil,
+		handlebars.Fragment{Name: "flash", Data: map[string]interface{}{"message": "hi"}},
+		handlebars.Fragment{Name: "flash", Target: `x" onclick="y`, Swap: "innerHTML"},
+	)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), `id="x&#34; onclick=&#34;y"`) {
+		t.Errorf("Expected the target escaped, got %q", buf.String())
+	}
+}
+
+func TestFragments_EchoContext_RespondsWithFragments(t *testing.T) {
+	e := echo.New()
+	e.Renderer = newCartRenderer()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest("POST", "/cart", nil), rec)
+
+	if err := handlebars.Fragments(c, http.StatusOK, cartFragments()...); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML) {
+		t.Errorf("Expected an HTML response, got %d %s", rec.Code, rec.Header().Get(echo.HeaderContentType))
+	}
+	if !strings.HasPrefix(rec.Body.String(), `<li>Book</li><div id="cart-count" hx-swap-oob="true">`) {
+		t.Errorf("Unexpected body %q", rec.Body.String())
+	}
+}
+
+func TestFragments_FailingFragment_WritesNoResponse(t *testing.T) {
+	e := echo.New()
+	e.Renderer = newCartRenderer()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest("POST", "/cart", nil), rec)
+
+	err := handlebars.Fragments(c, http.StatusOK,
+		handlebars.Fragment{Name: "cart/item"},
+		handlebars.Fragment{Name: "missing", Target: "missing"},
+	)
+
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if c.Response().Committed {
+		t.Errorf("Expected no response written, got %q", rec.Body.String())
+	}
+}
+
+func TestFragments_OtherRenderer_ReturnsError(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest("POST", "/cart", nil), httptest.NewRecorder())
+
+	if err := handlebars.Fragments(c, http.StatusOK, handlebars.Fragment{Name: "cart"}); err == nil {
+		t.Errorf("Expected an error without a HandlebarsRenderer")
+	}
+}
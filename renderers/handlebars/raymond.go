@@ -2,105 +2,575 @@ package handlebars
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/externals"
+	"github.com/BlindGarret/echorend/i18n"
 	"github.com/aymerick/raymond"
+	"github.com/aymerick/raymond/parser"
 	"github.com/labstack/echo/v4"
 )
 
+// HandlebarsRendererConfig is a configuration struct for creating a HandlebarsRenderer.
+type HandlebarsRendererConfig struct {
+	ViewGatherer    echorend.RawTemplateGatherer
+	PartialGatherer echorend.RawTemplateGatherer
+	LayoutGatherer  echorend.RawTemplateGatherer
+
+	// DefaultLayout is the layout views are wrapped in unless a render picks another one, see LayoutKey.
+	// Leave empty to render views without a layout by default.
+	DefaultLayout string
+
+	// CollisionPolicy decides what happens when templates share a name, within a gatherer or between views and partials.
+	// When a view and partial collide under CollisionFirstWins the view is rendered by that name, under CollisionLastWins
+	// the partial is. Either way the partial is still available to {{> partial}}.
+	// It is ignored by NewHandlebarsRendererFromBundle, as a bundle's collisions were resolved when it was made.
+	CollisionPolicy echorend.CollisionPolicy
+
+	// Helpers are registered on this renderer's templates only, rather than globally with raymond.RegisterHelper.
+	// A helper can read the echo.Context of the render through EchoContext.
+	Helpers map[string]interface{}
+
+	// RequestValues picks the values each render exposes to templates as @request, defaulting to the renderer's
+	// DefaultRequestValues. It is only called for renders whose view, layout or partials read @request.
+	RequestValues RequestValuesFunc
+	// CSRFKey is the echo.Context key DefaultRequestValues reads @request.csrf from, defaulting to DefaultCSRFKey.
+	// Set it to match the ContextKey of a customized CSRF middleware.
+	CSRFKey string
+
+	// Catalog enables translation with the {{t}} helper, and views and layouts overridden per locale, such as index.fr.
+	// Locale picks the locale of each render from the catalog's locales, defaulting to the Accept-Language header.
+	Catalog *i18n.Catalog
+	Locale  i18n.LocaleResolver
+
+	// HotReload watches the directories behind any WatchableGatherer and reparses changed templates.
+	// It is intended for development.
+	HotReload      bool
+	FileWatcher    externals.FileWatcher
+	ReloadDebounce time.Duration
+	// OnReload is called after every hot reload attempt, with the error if the reload failed.
+	// When a reload fails the renderer keeps serving the last good template set.
+	OnReload func(err error)
+
+	// Observer is told about every render, setup and reload, for metrics and tracing. See the observers packages.
+	Observer echorend.Observer
+
+	// Cache enables caching rendered output, cleared whenever templates reload. Leave nil to always render.
+	Cache *RenderCacheConfig
+
+	// HTMX renders just the fragment or partial an htmx request targets, when a view being rendered for a request
+	// with an HX-Request header defines a fragment or renders a partial named by its HX-Target header. See FragmentHelper.
+	HTMX bool
+}
+
 // HandlebarsRenderer is a renderer that uses the raymond library to render Handlebars templates.
 type HandlebarsRenderer struct {
-	templates       map[string]*raymond.Template
-	viewGatherer    echorend.RawTemplateGatherer
-	partialGatherer echorend.RawTemplateGatherer
+	// stats is first so its 64 bit counters are aligned for atomic access on 32 bit platforms.
+	stats renderStats
+
+	config HandlebarsRendererConfig
+
+	// set holds the current *templateSet. Builds swap in a whole new set, so renders never need a lock.
+	set atomic.Value
+	// helpers are the renderer's own helpers, registered on every template alongside the configured ones.
+	helpers map[string]interface{}
+	// routes names the routes of the Echo renders run in, for DefaultRequestValues.
+	routes routeIndex
+
+	// models are the data types of views declared with Register, guarded by modelsMutex
+	modelsMutex sync.RWMutex
+	models      map[string]reflect.Type
+
+	// build state, guarded by buildMutex
+	buildMutex sync.Mutex
+	parsed     map[templateKey]parsedTemplate
+	gathered   map[templateRole][]echorend.RawTemplateData
+	watch      externals.FileWatch
+	// attempted are the templates the last build parsed, whether or not it failed, and attemptedPartials the names
+	// of the partials it gathered, for CheckPartials.
+	attempted         map[templateKey]echorend.RawTemplateData
+	attemptedPartials map[string]bool
+}
+
+type templateRole int
+
+const (
+	roleView templateRole = iota
+	rolePartial
+	roleLayout
+)
+
+var templateRoles = []templateRole{roleView, rolePartial, roleLayout}
+
+func (role templateRole) String() string {
+	switch role {
+	case rolePartial:
+		return "partial"
+	case roleLayout:
+		return "layout"
+	default:
+		return "view"
+	}
+}
+
+type templateKey struct {
+	role templateRole
+	name string
+}
+
+// parsedTemplate is a template as parsed from its source, before any partials or helpers are registered on it.
+// The source parsed is the gathered source with its partial references qualified, see qualifyPartials.
+type parsedTemplate struct {
+	hash       string
+	source     string
+	references []partialReference
+	fragments  map[string]bool
+	// request is whether the template reads @request itself, and dynamicPartials whether it renders a partial
+	// named by a subexpression.
+	request         bool
+	dynamicPartials bool
+	tmpl            *raymond.Template
+}
+
+// templateSet is the immutable result of a build. It is never changed once stored, only replaced.
+type templateSet struct {
+	templates map[string]*compiledTemplate
+	// partials are the base partial templates registered on every other template.
+	partials map[string]*compiledTemplate
+	layouts  map[string]*compiledTemplate
+	// cache holds the set's rendered output when caching is enabled, see RenderCacheConfig.
+	cache *renderCache
+}
+
+func newTemplateSet() *templateSet {
+	return &templateSet{
+		templates: make(map[string]*compiledTemplate),
+		partials:  make(map[string]*compiledTemplate),
+		layouts:   make(map[string]*compiledTemplate),
+	}
+}
+
+// compiledTemplate is a template ready to render, with everything it needs registered on it, along with the
+// gathered data it was parsed from.
+type compiledTemplate struct {
+	tmpl *raymond.Template
+	raw  echorend.RawTemplateData
+	role templateRole
+	// fragments are the names of the fragments the template defines, see FragmentHelper, and references the
+	// partials it renders by name.
+	fragments  map[string]bool
+	references []partialReference
+	// request is whether the template reads @request, itself or through its partials, so bypasses the render cache.
+	request bool
 }
 
 func NewHandlebarsRenderer(
 	viewGatherer echorend.RawTemplateGatherer,
 	partialsGatherer echorend.RawTemplateGatherer,
 ) *HandlebarsRenderer {
-	return &HandlebarsRenderer{
-		templates:       make(map[string]*raymond.Template),
-		viewGatherer:    viewGatherer,
-		partialGatherer: partialsGatherer,
+	return NewHandlebarsRendererWithConfig(HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialsGatherer,
+	})
+}
+
+func NewHandlebarsRendererWithConfig(config HandlebarsRendererConfig) *HandlebarsRenderer {
+	r := &HandlebarsRenderer{
+		config:   defaultHandlebarsRendererConfig(config),
+		parsed:   make(map[templateKey]parsedTemplate),
+		gathered: make(map[templateRole][]echorend.RawTemplateData),
+		models:   make(map[string]reflect.Type),
 	}
+	r.helpers = r.ownHelpers()
+	r.set.Store(newTemplateSet())
+	return r
 }
 
-// Setup initializes the renderer by gathering templates from the view and partial gatherers and parsing them for render calls.
+// Setup initializes the renderer by gathering templates from the view, partial and layout gatherers and parsing them for render calls.
+// Partials are registered on each parsed template rather than globally, so renderers never share partials.
+// Every template is parsed even after a failure, and all the errors found are returned together as an *echorend.SetupError.
+// If hot reload is enabled, Setup also starts watching the gatherers' directories.
 func (r *HandlebarsRenderer) Setup() error {
-	if r.viewGatherer != nil {
-		views, err := r.viewGatherer.Gather()
-		if err != nil {
-			return err
-		}
-		for _, view := range views {
-			tmpl, err := raymond.Parse(view.TemplateData)
-			if err != nil {
-				return err
-			}
-			r.templates[view.TemplateName] = tmpl
-		}
-	}
+	r.buildMutex.Lock()
+	defer r.buildMutex.Unlock()
+	start := time.Now()
 
-	if r.partialGatherer != nil {
-		partials, err := r.partialGatherer.Gather()
+	gathered := make(map[templateRole][]echorend.RawTemplateData)
+	errs := r.validateHelpers()
+	for _, role := range templateRoles {
+		templates, err := gather(r.gatherer(role))
 		if err != nil {
-			return err
-		}
-		for _, partial := range partials {
-			tmpl, err := raymond.Parse(partial.TemplateData)
-			if err != nil {
-				return err
-			}
-			if _, exists := r.templates[partial.TemplateName]; exists {
-				return fmt.Errorf("partial %s already exists as a view", partial.TemplateName)
-			}
-			r.templates[partial.TemplateName] = tmpl
-			raymond.RegisterPartialTemplate(partial.TemplateName, tmpl)
+			errs = append(errs, err)
 		}
+		gathered[role] = templates
+	}
+	err := r.build(gathered, errs)
+	r.observeBuild(echorend.OperationSetup, start, err)
+	if err != nil {
+		return err
 	}
 
+	if r.config.HotReload && r.watch == nil {
+		return r.startWatch()
+	}
 	return nil
 }
 
-// MustSetup initializes the renderer by gathering templates from the view and partial gatherers
-// and parsing them for render calls. If an error occurs, it panics.
+// MustSetup initializes the renderer by gathering templates from the view, partial and layout gatherers
+// and parsing them for render calls. If an error occurs, it panics with the full report of every error.
 func (r *HandlebarsRenderer) MustSetup() {
 	if err := r.Setup(); err != nil {
 		panic(err)
 	}
 }
 
+// Reload re-gathers and reparses every template, then swaps the new template set in. It is safe to call from any
+// goroutine while rendering: renders already running finish with the set they started with, and if the reload
+// fails the current set is kept and the errors returned as from Setup.
+func (r *HandlebarsRenderer) Reload() error {
+	return r.reload(map[templateRole]bool{roleView: true, rolePartial: true, roleLayout: true})
+}
+
+// Close stops watching for template changes. It is a no-op unless hot reload is enabled.
+func (r *HandlebarsRenderer) Close() error {
+	r.buildMutex.Lock()
+	defer r.buildMutex.Unlock()
+
+	if r.watch == nil {
+		return nil
+	}
+	err := r.watch.Close()
+	r.watch = nil
+	return err
+}
+
 // Render renders a template with the given name and daata to the IO writer.
+// Views are wrapped in their layout, partials rendered as views never are.
+// With a Cache configured, renders already cached for the same key are written without rendering again.
+// A name such as page#results renders just the results fragment of the page view, see FragmentHelper.
 // this function is designed to slot directly into echo as a renderer
-func (r *HandlebarsRenderer) Render(w io.Writer, name string, data interface{}, _ echo.Context) error {
-	tmpl, ok := r.templates[name]
+func (r *HandlebarsRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	set := r.current()
+	return r.renderObserved(set, w, r.htmxSelector(set, name, c), data, c, false)
+}
+
+// renderObserved renders through the cache, telling the observer about the render. Bare renders skip the layout.
+func (r *HandlebarsRenderer) renderObserved(set *templateSet, w io.Writer, name string, data interface{}, c echo.Context, bare bool) error {
+	if r.config.Observer == nil {
+		return r.renderCached(set, w, name, data, c, bare)
+	}
+	start := time.Now()
+	counter := &countingWriter{w: w}
+	err := r.renderCached(set, counter, name, data, c, bare)
+	r.config.Observer.Observe(echorend.Event{
+		Operation: echorend.OperationRender,
+		Template:  name,
+		Start:     start,
+		Duration:  time.Since(start),
+		Size:      counter.n,
+		Err:       err,
+		Context:   requestContext(c),
+	})
+	return err
+}
+
+func (r *HandlebarsRenderer) render(set *templateSet, w io.Writer, name string, data interface{}, c echo.Context, bare bool) error {
+	layoutName := ""
+	if !bare {
+		layoutName = echorend.CanonicalName(r.layoutName(data, c))
+	}
+	state := newRenderState(c)
+	state.locale = r.locale(c)
+	// @request is only built for renders reading it, as building it can be expensive
+	if set.readsRequest(name, layoutName, state.locale) {
+		state.request = r.requestValues(c)
+	}
+	name, fragment := SplitFragment(echorend.CanonicalName(name))
+	tmpl, ok := localized(set.templates, name, state.locale)
 	if !ok {
 		return fmt.Errorf("template %s not found", name)
 	}
+	if fragment != "" {
+		return r.renderFragment(set, w, name, tmpl, fragment, data, state)
+	}
 
-	str, err := tmpl.Exec(data)
+	str, err := tmpl.tmpl.ExecWith(data, state.frame())
 	if err != nil {
-		return err
+		return fmt.Errorf("rendering %s from %s: %w", name, tmpl.raw.Source, err)
 	}
 
-	_, err = w.Write([]byte(str))
+	if tmpl.role != rolePartial && !bare {
+		if layoutName != "" {
+			layout, ok := localized(set.layouts, layoutName, state.locale)
+			if !ok {
+				return fmt.Errorf("layout %s not found", layoutName)
+			}
+			state.body = str
+			if str, err = layout.tmpl.ExecWith(data, state.frame()); err != nil {
+				return fmt.Errorf("rendering layout %s from %s: %w", layoutName, layout.raw.Source, err)
+			}
+		}
+	}
+
+	// raymond can only render to a string, so write it without copying to a []byte when w supports it,
+	// which the bytes.Buffer echo renders into does.
+	_, err = io.WriteString(w, str)
 	return err
 }
 
 // CheckRenders is a convience tool for rendering all templates with no data
 // to ensure they aren't referencing non-existant partials.
+// Partial references and helper calls are also checked statically, including those inside blocks a nil data render skips,
+// as are the field paths of views declared with Register.
+// Templates already failing the static check aren't rendered, so each problem is only reported once.
 func (r *HandlebarsRenderer) CheckRenders() []error {
-	errs := make([]error, 0)
-	for name, _ := range r.templates {
+	set := r.current()
+	errs, failing := r.checkPartials(set)
+	errs = append(errs, r.checkHelpers(set)...)
+	errs = append(errs, r.checkModels(set)...)
+	for name, tmpl := range set.templates {
+		if failing[templateKey{role: tmpl.role, name: name}] {
+			continue
+		}
 		buf := new(bytes.Buffer)
-		err := r.Render(buf, name, nil, nil)
+		err := r.render(set, buf, name, nil, nil, false)
 		if err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	for name, layout := range set.layouts {
+		if failing[templateKey{role: roleLayout, name: name}] {
+			continue
+		}
+		if _, err := layout.tmpl.ExecWith(nil, newRenderState(nil).frame()); err != nil {
+			errs = append(errs, fmt.Errorf("rendering layout %s from %s: %w", name, layout.raw.Source, err))
+		}
+	}
+
 	return errs
 }
+
+// current returns the template set renders should use.
+func (r *HandlebarsRenderer) current() *templateSet {
+	return r.set.Load().(*templateSet)
+}
+
+func (r *HandlebarsRenderer) gatherer(role templateRole) echorend.RawTemplateGatherer {
+	switch role {
+	case rolePartial:
+		return r.config.PartialGatherer
+	case roleLayout:
+		return r.config.LayoutGatherer
+	default:
+		return r.config.ViewGatherer
+	}
+}
+
+// build parses the gathered templates into a new template set and swaps it in. Errors found while gathering are
+// passed in, so that they are reported along with any found here and the template set is only swapped when there
+// are none. Sources unchanged since the last build reuse their parsed template. Callers must hold buildMutex.
+func (r *HandlebarsRenderer) build(gathered map[templateRole][]echorend.RawTemplateData, errs []error) error {
+	resolved, err := r.resolveCollisions(canonicalNames(gathered))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	partialNames := make(map[string]bool)
+	for _, data := range resolved[rolePartial] {
+		partialNames[data.TemplateName] = true
+	}
+	hasPartial := func(name string) bool {
+		return partialNames[name]
+	}
+
+	parsed := make(map[templateKey]parsedTemplate)
+	bases := make(map[templateRole]map[string]*raymond.Template)
+	raws := make(map[templateKey]echorend.RawTemplateData)
+	for _, role := range templateRoles {
+		bases[role] = make(map[string]*raymond.Template)
+		for _, data := range resolved[role] {
+			key := templateKey{role: role, name: data.TemplateName}
+			hash := data.Hash
+			if hash == "" {
+				hash = echorend.ContentHash(data.TemplateData)
+			}
+			existing, ok := r.parsed[key]
+			if !ok || existing.hash != hash {
+				program, err := parser.Parse(data.TemplateData)
+				if err != nil {
+					errs = append(errs, newTemplateError(data, err))
+					continue
+				}
+				existing = parsedTemplate{hash: hash, fragments: fragmentNames(data.TemplateData), references: partialReferences(program)}
+				existing.request, existing.dynamicPartials = requestUsage(program)
+			}
+			if source := qualifyPartials(data, existing.references, hasPartial); existing.tmpl == nil || existing.source != source {
+				tmpl, err := raymond.Parse(source)
+				if err != nil {
+					errs = append(errs, newTemplateError(data, err))
+					continue
+				}
+				existing.source = source
+				existing.tmpl = tmpl
+			}
+			parsed[key] = existing
+			bases[role][data.TemplateName] = existing.tmpl
+			raws[key] = data
+		}
+	}
+
+	r.attempted = raws
+	r.attemptedPartials = partialNames
+	if len(errs) > 0 {
+		return &echorend.SetupError{Errors: errs}
+	}
+
+	partials := make(map[string]*compiledTemplate)
+	for name, base := range bases[rolePartial] {
+		partials[name] = &compiledTemplate{tmpl: base, raw: raws[templateKey{role: rolePartial, name: name}], role: rolePartial}
+	}
+
+	scoped := requestScoped(parsed, raws, hasPartial)
+
+	// raymond resolves partials and helpers against the template being executed, including partials nested in
+	// partials, so every template needs the full partial set registered on it. Registering on a clone keeps the
+	// parsed templates clean for reuse by later builds.
+	prepare := func(role templateRole, name string, base *raymond.Template) *compiledTemplate {
+		tmpl := base.Clone()
+		for partialName, partial := range partials {
+			tmpl.RegisterPartialTemplate(partialName, partial.tmpl)
+		}
+		tmpl.RegisterHelpers(r.helpers)
+		if role == roleLayout {
+			tmpl.RegisterHelpers(layoutHelpers)
+		}
+		tmpl.RegisterHelpers(r.config.Helpers)
+		key := templateKey{role: role, name: name}
+		return &compiledTemplate{
+			tmpl:       tmpl,
+			raw:        raws[key],
+			role:       role,
+			fragments:  parsed[key].fragments,
+			references: parsed[key].references,
+			request:    scoped[key],
+		}
+	}
+	templates := make(map[string]*compiledTemplate)
+	for _, role := range []templateRole{roleView, rolePartial} {
+		for name, base := range bases[role] {
+			_, isView := bases[roleView][name]
+			if role == rolePartial && isView && r.config.CollisionPolicy == echorend.CollisionFirstWins {
+				continue
+			}
+			templates[name] = prepare(role, name, base)
+		}
+	}
+	layouts := make(map[string]*compiledTemplate)
+	for name, base := range bases[roleLayout] {
+		layouts[name] = prepare(roleLayout, name, base)
+	}
+
+	r.parsed = parsed
+	r.gathered = gathered
+
+	r.set.Store(&templateSet{
+		templates: templates,
+		partials:  partials,
+		layouts:   layouts,
+		cache:     newRenderCache(r.config.Cache),
+	})
+	return nil
+}
+
+// resolveCollisions applies the collision policy within each role's templates, and between views and partials,
+// which share a name when rendering. Every collision is reported together, alongside the templates as gathered.
+func (r *HandlebarsRenderer) resolveCollisions(gathered map[templateRole][]echorend.RawTemplateData) (map[templateRole][]echorend.RawTemplateData, error) {
+	resolved := make(map[templateRole][]echorend.RawTemplateData)
+	collisions := make([]echorend.Collision, 0)
+	for _, role := range templateRoles {
+		templates, err := echorend.ResolveCollisions(withSources(gathered[role], role), r.config.CollisionPolicy)
+		var collisionErr *echorend.CollisionError
+		if errors.As(err, &collisionErr) {
+			collisions = append(collisions, collisionErr.Collisions...)
+		}
+		resolved[role] = templates
+	}
+
+	if r.config.CollisionPolicy == echorend.CollisionFail {
+		views := make(map[string]string)
+		for _, view := range resolved[roleView] {
+			views[view.TemplateName] = view.Source
+		}
+		for _, partial := range resolved[rolePartial] {
+			if source, exists := views[partial.TemplateName]; exists {
+				collisions = append(collisions, echorend.Collision{
+					TemplateName: partial.TemplateName,
+					Sources:      []string{source, partial.Source},
+				})
+			}
+		}
+	}
+
+	if len(collisions) > 0 {
+		return resolved, &echorend.CollisionError{Collisions: collisions}
+	}
+	return resolved, nil
+}
+
+// withSources labels templates which don't know their source with their role and position, so collisions
+// between them can still be told apart.
+func withSources(templates []echorend.RawTemplateData, role templateRole) []echorend.RawTemplateData {
+	labelled := make([]echorend.RawTemplateData, len(templates))
+	for i, template := range templates {
+		if template.Source == "" {
+			template.Source = fmt.Sprintf("%s #%d", role, i+1)
+		}
+		labelled[i] = template
+	}
+	return labelled
+}
+
+func defaultHandlebarsRendererConfig(config HandlebarsRendererConfig) HandlebarsRendererConfig {
+	if config.HotReload && config.FileWatcher == nil {
+		config.FileWatcher = &externals.StdFileWatcher{}
+	}
+
+	if config.ReloadDebounce == 0 {
+		config.ReloadDebounce = 50 * time.Millisecond
+	}
+
+	if config.Locale == nil {
+		config.Locale = i18n.AcceptLanguage
+	}
+
+	if config.CSRFKey == "" {
+		config.CSRFKey = DefaultCSRFKey
+	}
+
+	config.Cache = defaultRenderCacheConfig(config.Cache)
+
+	if config.OnReload == nil {
+		config.OnReload = func(err error) {
+			if err != nil {
+				log.Printf("echorend: template reload failed, serving previous templates: %v", err)
+			}
+		}
+	}
+
+	return config
+}
+
+func gather(gatherer echorend.RawTemplateGatherer) ([]echorend.RawTemplateData, error) {
+	if gatherer == nil {
+		return nil, nil
+	}
+	return gatherer.Gather()
+}
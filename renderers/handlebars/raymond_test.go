@@ -3,16 +3,22 @@ package handlebars_test
 import (
 	"bytes"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/BlindGarret/echorend"
 	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
 )
 
 func renderToString(name string, data interface{}, renderer *handlebars.HandlebarsRenderer) (string, error) {
+	return renderToStringWithContext(name, data, renderer, nil)
+}
+
+func renderToStringWithContext(name string, data interface{}, renderer *handlebars.HandlebarsRenderer, c echo.Context) (string, error) {
 	buf := new(bytes.Buffer)
 
-	err := renderer.Render(buf, name, data, nil)
+	err := renderer.Render(buf, name, data, c)
 	if err != nil {
 		return "", err
 	}
@@ -272,3 +278,200 @@ func TestHandlebarsCheckRenders_RunWithInvalidTemplates_ReturnsErrors(t *testing
 		t.Errorf("Expected 1 error, got %d", len(errs))
 	}
 }
+
+func TestHandlebarsRendererSetup_TwoRenderersShareAPartialName_EachRendersItsOwnPartial(t *testing.T) {
+	renderers := make([]*handlebars.HandlebarsRenderer, 0)
+	for _, content := range []string{"first", "second"} {
+		viewGatherer := NewMockTemplateGatherer()
+		viewGatherer.AddTemplate(echorend.RawTemplateData{
+			TemplateName: "shared-partial-view",
+			TemplateData: "<HTML>{{> shared-partial}}</HTML>",
+		})
+		partialGatherer := NewMockTemplateGatherer()
+		partialGatherer.AddTemplate(echorend.RawTemplateData{
+			TemplateName: "shared-partial",
+			TemplateData: content,
+		})
+		renderer := handlebars.NewHandlebarsRenderer(viewGatherer, partialGatherer)
+		renderer.MustSetup()
+		renderers = append(renderers, renderer)
+	}
+
+	first, err := renderToString("shared-partial-view", nil, renderers[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := renderToString("shared-partial-view", nil, renderers[1])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first != "<HTML>first</HTML>" {
+		t.Errorf("Expected first renderer to use its own partial, got %s", first)
+	}
+	if second != "<HTML>second</HTML>" {
+		t.Errorf("Expected second renderer to use its own partial, got %s", second)
+	}
+}
+
+func TestHandlebarsRendererRender_PartialReferencesPartial_RendersNestedPartial(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "nested-partial-view",
+		TemplateData: "<HTML>{{> outer-partial}}</HTML>",
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "outer-partial",
+		TemplateData: "<div>{{> inner-partial}}</div>",
+	})
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "inner-partial",
+		TemplateData: "<p>inner</p>",
+	})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, partialGatherer)
+	renderer.MustSetup()
+
+	out, err := renderToString("nested-partial-view", nil, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<HTML><div><p>inner</p></div></HTML>" {
+		t.Errorf("Unexpected render output %s", out)
+	}
+}
+
+func TestHandlebarsRendererSetup_DuplicateViewsAndPartials_ReportsEveryCollision(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view", TemplateData: "one", Source: "views/dup-view.hbs"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view", TemplateData: "two", Source: "views/dup-view.handlebars"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-shared", TemplateData: "view"})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-shared", TemplateData: "partial"})
+
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, partialGatherer)
+	err := renderer.Setup()
+
+	var collisionErr *echorend.CollisionError
+	if !errors.As(err, &collisionErr) {
+		t.Fatalf("Expected CollisionError, got %v", err)
+	}
+	if len(collisionErr.Collisions) != 2 {
+		t.Fatalf("Expected 2 collisions, got %v", collisionErr.Collisions)
+	}
+	first, second := collisionErr.Collisions[0], collisionErr.Collisions[1]
+	if first.TemplateName != "dup-view" || first.Sources[0] != "views/dup-view.hbs" || first.Sources[1] != "views/dup-view.handlebars" {
+		t.Errorf("Unexpected view collision %v", first)
+	}
+	if second.TemplateName != "dup-shared" || second.Sources[0] != "view #3" || second.Sources[1] != "partial #1" {
+		t.Errorf("Unexpected view and partial collision %v", second)
+	}
+}
+
+func TestHandlebarsRendererSetup_DuplicateViewsWithLastWinsPolicy_RendersLast(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view2", TemplateData: "one"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-view2", TemplateData: "two"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		CollisionPolicy: echorend.CollisionLastWins,
+	})
+	renderer.MustSetup()
+
+	out, err := renderToString("dup-view2", nil, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "two" {
+		t.Errorf("Expected last view, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererSetup_ViewAndPartialCollideWithFirstWinsPolicy_ViewRendersAndPartialStillUsable(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-shared2", TemplateData: "view {{> dup-shared2}}"})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "dup-shared2", TemplateData: "partial"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		CollisionPolicy: echorend.CollisionFirstWins,
+	})
+	renderer.MustSetup()
+
+	out, err := renderToString("dup-shared2", nil, renderer)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "view partial" {
+		t.Errorf("Expected view using partial, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererSetup_BadTemplate_ErrorNamesSourceFile(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "source-view1",
+		TemplateData: "<HTML>{{herp}</HTML>",
+		Source:       "templates/views/source-view1.hbs",
+	})
+
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, nil)
+	err := renderer.Setup()
+
+	if err == nil || !strings.Contains(err.Error(), "templates/views/source-view1.hbs") {
+		t.Errorf("Expected error naming the source file, got %v", err)
+	}
+}
+
+func TestHandlebarsRendererRender_RenderFails_ErrorNamesSourceFile(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "source-view2",
+		TemplateData: "<HTML>{{> missing-partial}}</HTML>",
+		Source:       "templates/views/source-view2.hbs",
+	})
+	renderer := handlebars.NewHandlebarsRenderer(viewGatherer, nil)
+	renderer.MustSetup()
+
+	_, err := renderToString("source-view2", nil, renderer)
+
+	if err == nil || !strings.Contains(err.Error(), "templates/views/source-view2.hbs") {
+		t.Errorf("Expected error naming the source file, got %v", err)
+	}
+}
+
+func TestHandlebarsRendererTemplates_AfterSetup_ListsEveryTemplate(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "list-view2", Source: "views/list-view2.hbs"})
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "list-view1", Source: "views/list-view1.hbs"})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "list-partial", Source: "partials/list-partial.hbs"})
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "list-layout", TemplateData: "{{{@body}}}"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		LayoutGatherer:  layoutGatherer,
+	})
+	renderer.MustSetup()
+
+	templates := renderer.Templates()
+
+	got := make([]string, 0)
+	for _, template := range templates {
+		got = append(got, template.Role+" "+template.Name+" "+template.Source)
+	}
+	expected := []string{
+		"view list-view1 views/list-view1.hbs",
+		"view list-view2 views/list-view2.hbs",
+		"partial list-partial partials/list-partial.hbs",
+		"layout list-layout layout #1",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,103 @@
+package handlebars
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+)
+
+// startWatch begins watching the directories of any watchable gatherers. Callers must hold buildMutex.
+func (r *HandlebarsRenderer) startWatch() error {
+	dirs := make([]string, 0)
+	for _, role := range templateRoles {
+		dirs = append(dirs, watchDirs(r.gatherer(role))...)
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	watch, err := r.config.FileWatcher.Watch(dirs)
+	if err != nil {
+		return err
+	}
+	r.watch = watch
+
+	go r.watchLoop(watch.Events(), watch.Errors())
+	return nil
+}
+
+// watchLoop collects change events until they go quiet for the debounce period, then reloads
+// whichever gatherers the changed paths belong to.
+func (r *HandlebarsRenderer) watchLoop(events <-chan string, errs <-chan error) {
+	changed := make(map[templateRole]bool)
+	var timer <-chan time.Time
+
+	for {
+		select {
+		case path, ok := <-events:
+			if !ok {
+				return
+			}
+			for _, role := range templateRoles {
+				if ownsPath(r.gatherer(role), path) {
+					changed[role] = true
+				}
+			}
+			timer = time.After(r.config.ReloadDebounce)
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			r.config.OnReload(err)
+		case <-timer:
+			if len(changed) > 0 {
+				r.config.OnReload(r.reload(changed))
+			}
+			changed, timer = make(map[templateRole]bool), nil
+		}
+	}
+}
+
+// reload re-gathers the changed gatherers and rebuilds the template set, reusing the previous
+// gather results for the others. On failure the current template set is left in place.
+func (r *HandlebarsRenderer) reload(changed map[templateRole]bool) error {
+	r.buildMutex.Lock()
+	defer r.buildMutex.Unlock()
+	start := time.Now()
+
+	gathered := make(map[templateRole][]echorend.RawTemplateData)
+	errs := r.validateHelpers()
+	for _, role := range templateRoles {
+		gathered[role] = r.gathered[role]
+		if !changed[role] {
+			continue
+		}
+		templates, err := gather(r.gatherer(role))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		gathered[role] = templates
+	}
+	err := r.build(gathered, errs)
+	r.observeBuild(echorend.OperationReload, start, err)
+	return err
+}
+
+func watchDirs(gatherer echorend.RawTemplateGatherer) []string {
+	if watchable, ok := gatherer.(echorend.WatchableGatherer); ok {
+		return watchable.WatchDirs()
+	}
+	return nil
+}
+
+func ownsPath(gatherer echorend.RawTemplateGatherer, path string) bool {
+	for _, dir := range watchDirs(gatherer) {
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
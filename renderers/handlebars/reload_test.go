@@ -0,0 +1,259 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+)
+
+func newHotReloadRenderer(t *testing.T, views *MockWatchableGatherer, partials *MockWatchableGatherer) (*handlebars.HandlebarsRenderer, *MockFileWatcher, chan error) {
+	watcher := NewMockFileWatcher()
+	reloads := make(chan error, 1)
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    views,
+		PartialGatherer: partials,
+		HotReload:       true,
+		FileWatcher:     watcher,
+		ReloadDebounce:  time.Millisecond,
+		OnReload: func(err error) {
+			reloads <- err
+		},
+	})
+	renderer.MustSetup()
+	t.Cleanup(func() { renderer.Close() })
+	return renderer, watcher, reloads
+}
+
+func waitForReload(t *testing.T, reloads chan error) error {
+	select {
+	case err := <-reloads:
+		return err
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for reload")
+		return nil
+	}
+}
+
+func TestHandlebarsRendererHotReload_Setup_WatchesGathererDirs(t *testing.T) {
+	views := NewMockWatchableGatherer("views")
+	partials := NewMockWatchableGatherer("partials")
+
+	_, watcher, _ := newHotReloadRenderer(t, views, partials)
+
+	if len(watcher.watchedDirs) != 2 || watcher.watchedDirs[0] != "views" || watcher.watchedDirs[1] != "partials" {
+		t.Errorf("Expected views and partials to be watched, got %v", watcher.watchedDirs)
+	}
+}
+
+func TestHandlebarsRendererHotReload_ViewChanges_RendersNewView(t *testing.T) {
+	views := NewMockWatchableGatherer("views")
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-view1", TemplateData: "before"})
+	partials := NewMockWatchableGatherer("partials")
+	renderer, watcher, reloads := newHotReloadRenderer(t, views, partials)
+
+	views.SetTemplates(echorend.RawTemplateData{TemplateName: "reload-view1", TemplateData: "after"})
+	watcher.Change("views/reload-view1.hbs")
+	if err := waitForReload(t, reloads); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out, err := renderToString("reload-view1", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "after" {
+		t.Errorf("Expected reloaded view, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererHotReload_PartialChanges_ViewsUseNewPartial(t *testing.T) {
+	views := NewMockWatchableGatherer("views")
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-view2", TemplateData: "<p>{{> reload-partial2}}</p>"})
+	partials := NewMockWatchableGatherer("partials")
+	partials.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-partial2", TemplateData: "before"})
+	renderer, watcher, reloads := newHotReloadRenderer(t, views, partials)
+
+	partials.SetTemplates(echorend.RawTemplateData{TemplateName: "reload-partial2", TemplateData: "after"})
+	watcher.Change("partials/reload-partial2.hbs")
+	if err := waitForReload(t, reloads); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out, err := renderToString("reload-view2", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "<p>after</p>" {
+		t.Errorf("Expected view to use reloaded partial, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererHotReload_ChangeIsBroken_ReportsErrorAndKeepsLastGoodTemplates(t *testing.T) {
+	views := NewMockWatchableGatherer("views")
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-view3", TemplateData: "good"})
+	partials := NewMockWatchableGatherer("partials")
+	renderer, watcher, reloads := newHotReloadRenderer(t, views, partials)
+
+	views.SetTemplates(echorend.RawTemplateData{TemplateName: "reload-view3", TemplateData: "{{broken}"})
+	watcher.Change("views/reload-view3.hbs")
+	if err := waitForReload(t, reloads); err == nil {
+		t.Fatalf("Expected reload error, got nil")
+	}
+
+	out, err := renderToString("reload-view3", nil, renderer)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out != "good" {
+		t.Errorf("Expected last good view, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererHotReload_ChangeOutsideWatchedDirs_DoesNotReload(t *testing.T) {
+	views := NewMockWatchableGatherer("views")
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-view4", TemplateData: "before"})
+	partials := NewMockWatchableGatherer("partials")
+	renderer, watcher, reloads := newHotReloadRenderer(t, views, partials)
+
+	views.SetTemplates(echorend.RawTemplateData{TemplateName: "reload-view4", TemplateData: "after"})
+	watcher.Change("elsewhere/reload-view4.hbs")
+
+	select {
+	case err := <-reloads:
+		t.Fatalf("Expected no reload, got one with error %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	out, _ := renderToString("reload-view4", nil, renderer)
+	if out != "before" {
+		t.Errorf("Expected original view, got %s", out)
+	}
+}
+
+func TestHandlebarsRendererClose_HotReloadEnabled_ClosesWatch(t *testing.T) {
+	views := NewMockWatchableGatherer("views")
+	partials := NewMockWatchableGatherer("partials")
+	renderer, watcher, _ := newHotReloadRenderer(t, views, partials)
+
+	if err := renderer.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !watcher.closed {
+		t.Errorf("Expected watch to be closed")
+	}
+}
+
+func TestHandlebarsRendererReload_ChangedTemplates_RendersNewTemplates(t *testing.T) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-view", TemplateData: "old"})
+	renderer := handlebars.NewHandlebarsRenderer(views, NewMockTemplateGatherer())
+	renderer.MustSetup()
+	views.SetTemplates(echorend.RawTemplateData{TemplateName: "reload-view", TemplateData: "new"})
+
+	err := renderer.Reload()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	buf := new(bytes.Buffer)
+	_ = renderer.Render(buf, "reload-view", nil, nil)
+	if buf.String() != "new" {
+		t.Errorf("Expected new, got %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererReload_BrokenTemplate_ReturnsErrorAndKeepsCurrentSet(t *testing.T) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-view", TemplateData: "old"})
+	renderer := handlebars.NewHandlebarsRenderer(views, NewMockTemplateGatherer())
+	renderer.MustSetup()
+	views.SetTemplates(echorend.RawTemplateData{TemplateName: "reload-view", TemplateData: "{{#if}}"})
+
+	err := renderer.Reload()
+
+	var setupErr *echorend.SetupError
+	if !errors.As(err, &setupErr) {
+		t.Fatalf("Expected SetupError, got %v", err)
+	}
+	buf := new(bytes.Buffer)
+	_ = renderer.Render(buf, "reload-view", nil, nil)
+	if buf.String() != "old" {
+		t.Errorf("Expected old, got %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererReload_InvalidHelperWithoutSetup_ReturnsError(t *testing.T) {
+	views := NewMockTemplateGatherer()
+	views.AddTemplate(echorend.RawTemplateData{TemplateName: "reload-view", TemplateData: "view"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer: views,
+		Helpers:      map[string]interface{}{"bad": 42},
+	})
+
+	err := renderer.Reload()
+
+	var setupErr *echorend.SetupError
+	if !errors.As(err, &setupErr) {
+		t.Fatalf("Expected SetupError, got %v", err)
+	}
+}
+
+// Run with -race. Each version's view and partial change together, so a render mixing two versions means a
+// render saw a partly swapped template set.
+func TestHandlebarsRendererReload_DuringRenders_EachRenderSeesOneSet(t *testing.T) {
+	views := NewMockTemplateGatherer()
+	partials := NewMockTemplateGatherer()
+	setVersion := func(version string) {
+		views.SetTemplates(echorend.RawTemplateData{TemplateName: "race-view", TemplateData: version + "{{> race-partial}}"})
+		partials.SetTemplates(echorend.RawTemplateData{TemplateName: "race-partial", TemplateData: version})
+	}
+	setVersion("a")
+	renderer := handlebars.NewHandlebarsRenderer(views, partials)
+	renderer.MustSetup()
+
+	stop := make(chan struct{})
+	var reloads sync.WaitGroup
+	reloads.Add(1)
+	go func() {
+		defer reloads.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			setVersion([]string{"a", "b"}[i%2])
+			if err := renderer.Reload(); err != nil {
+				t.Errorf("Expected no reload error, got %v", err)
+				return
+			}
+		}
+	}()
+
+	var renders sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		renders.Add(1)
+		go func() {
+			defer renders.Done()
+			for j := 0; j < 200; j++ {
+				buf := new(bytes.Buffer)
+				if err := renderer.Render(buf, "race-view", nil, nil); err != nil {
+					t.Errorf("Expected no render error, got %v", err)
+					return
+				}
+				if out := buf.String(); out != "aa" && out != "bb" {
+					t.Errorf("Expected a consistent template set, got %s", out)
+					return
+				}
+			}
+		}()
+	}
+	renders.Wait()
+	close(stop)
+	reloads.Wait()
+}
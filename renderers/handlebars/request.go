@@ -0,0 +1,126 @@
+package handlebars
+
+import (
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestDataKey is the private data key request values are available under in templates, as @request.
+//
+//	<a href="{{@request.path}}?page={{@request.query.page}}">
+const RequestDataKey = "request"
+
+// UserKey is the echo.Context key the authenticated user is read from, matching echo-jwt's default.
+const UserKey = "user"
+
+// FlashKey is the echo.Context key flash messages are read from. Handlers or middleware set it with c.Set.
+const FlashKey = "flash"
+
+// DefaultCSRFKey is the echo.Context key the CSRF token is read from unless HandlebarsRendererConfig.CSRFKey is set,
+// matching the default of echo's CSRF middleware.
+const DefaultCSRFKey = "csrf"
+
+// RequestValuesFunc builds the values a render exposes as @request from its echo.Context.
+// It is never called with a nil context, nor for renders whose templates don't read @request.
+type RequestValuesFunc func(c echo.Context) map[string]interface{}
+
+// DefaultRequestValues exposes the current request to templates as:
+//
+//	@request.path       the URL path
+//	@request.query      the first value of each query parameter
+//	@request.route      the name of the matched route
+//	@request.csrf       the token set under the configured CSRFKey
+//	@request.user       the value set under UserKey
+//	@request.requestId  the request ID, as set by echo's RequestID middleware
+//	@request.flash      the value set under FlashKey
+//
+// They are the values renders expose unless RequestValues is set, which can call this to extend them.
+func (r *HandlebarsRenderer) DefaultRequestValues(c echo.Context) map[string]interface{} {
+	req := c.Request()
+	query := make(map[string]string)
+	for key, values := range req.URL.Query() {
+		if len(values) > 0 {
+			query[key] = values[0]
+		}
+	}
+
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	if requestID == "" {
+		requestID = req.Header.Get(echo.HeaderXRequestID)
+	}
+
+	return map[string]interface{}{
+		"path":      req.URL.Path,
+		"query":     query,
+		"route":     r.routes.name(c),
+		"csrf":      c.Get(r.config.CSRFKey),
+		"user":      c.Get(UserKey),
+		"requestId": requestID,
+		"flash":     c.Get(FlashKey),
+	}
+}
+
+// ResetRouteNames drops the renderer's index of route names, which is built the first time a render reads
+// @request. Call it after adding routes to an Echo the renderer has already rendered for, so they are named too.
+func (r *HandlebarsRenderer) ResetRouteNames() {
+	r.routes.reset()
+}
+
+// routeIndex holds the names of an Echo's routes by method and path, so renders don't copy its route table.
+// A renderer normally serves one Echo, so only the last one rendered for is indexed.
+type routeIndex struct {
+	mutex sync.RWMutex
+	echo  *echo.Echo
+	names map[string]string
+}
+
+func (ri *routeIndex) name(c echo.Context) string {
+	e, path := c.Echo(), c.Path()
+	if e == nil || path == "" {
+		return ""
+	}
+	key := c.Request().Method + " " + path
+
+	ri.mutex.RLock()
+	if ri.echo == e {
+		name := ri.names[key]
+		ri.mutex.RUnlock()
+		return name
+	}
+	ri.mutex.RUnlock()
+
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+	if ri.echo != e {
+		ri.names = make(map[string]string)
+		for _, route := range e.Routes() {
+			ri.names[route.Method+" "+route.Path] = route.Name
+		}
+		ri.echo = e
+	}
+	return ri.names[key]
+}
+
+func (ri *routeIndex) reset() {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+	ri.echo, ri.names = nil, nil
+}
+
+// requestValues builds the @request values for a render, which are empty outside of a request.
+func (r *HandlebarsRenderer) requestValues(c echo.Context) map[string]interface{} {
+	if c == nil {
+		return map[string]interface{}{}
+	}
+	var values map[string]interface{}
+	if r.config.RequestValues != nil {
+		values = r.config.RequestValues(c)
+	} else {
+		values = r.DefaultRequestValues(c)
+	}
+	if values == nil {
+		return map[string]interface{}{}
+	}
+	return values
+}
@@ -0,0 +1,204 @@
+package handlebars_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BlindGarret/echorend"
+	"github.com/BlindGarret/echorend/renderers/handlebars"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func newRequestRenderer(view string, requestValues handlebars.RequestValuesFunc) *handlebars.HandlebarsRenderer {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "request-view",
+		TemplateData: view,
+	})
+	partialGatherer := NewMockTemplateGatherer()
+	partialGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "request-partial",
+		TemplateData: "[{{@request.path}}]",
+	})
+	layoutGatherer := NewMockTemplateGatherer()
+	layoutGatherer.AddTemplate(echorend.RawTemplateData{
+		TemplateName: "request-layout",
+		TemplateData: "<body data-csrf=\"{{@request.csrf}}\">{{{@body}}}</body>",
+	})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer:    viewGatherer,
+		PartialGatherer: partialGatherer,
+		LayoutGatherer:  layoutGatherer,
+		RequestValues:   requestValues,
+	})
+	renderer.MustSetup()
+	return renderer
+}
+
+func newRequestContext(target string) (*echo.Echo, echo.Context) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest("GET", target, nil), httptest.NewRecorder())
+	return e, c
+}
+
+func TestHandlebarsRendererRender_DefaultRequestValues_ExposesRequest(t *testing.T) {
+	renderer := newRequestRenderer("{{@request.path}} {{@request.query.page}} {{@request.route}} {{@request.user.name}} {{@request.requestId}} {{@request.flash}}", nil)
+	e, c := newRequestContext("/users?page=2&page=3")
+	e.GET("/users", func(echo.Context) error { return nil }).Name = "users.index"
+	c.SetPath("/users")
+	c.Set(handlebars.UserKey, map[string]string{"name": "bob"})
+	c.Set(handlebars.FlashKey, "Saved")
+	c.Response().Header().Set(echo.HeaderXRequestID, "req-1")
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "request-view", nil, c)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "/users 2 users.index bob req-1 Saved" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_RequestValues_AvailableInBlocksPartialsAndLayouts(t *testing.T) {
+	renderer := newRequestRenderer("{{#each items}}{{> request-partial}}{{/each}}", nil)
+	_, c := newRequestContext("/cart")
+	c.Set(handlebars.DefaultCSRFKey, "token-1")
+	data := map[string]interface{}{
+		"items":              []int{1, 2},
+		handlebars.LayoutKey: "request-layout",
+	}
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "request-view", data, c)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "<body data-csrf=\"token-1\">[/cart][/cart]</body>" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_RequestKeyInData_DoesNotCollide(t *testing.T) {
+	renderer := newRequestRenderer("{{request}} {{@request.path}}", nil)
+	_, c := newRequestContext("/home")
+	buf := new(bytes.Buffer)
+
+	_ = renderer.Render(buf, "request-view", map[string]interface{}{"request": "mine"}, c)
+
+	if buf.String() != "mine /home" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_CustomRequestValues_ReplacesDefaults(t *testing.T) {
+	renderer := newRequestRenderer("{{@request.path}}|{{@request.tenant}}", func(c echo.Context) map[string]interface{} {
+		return map[string]interface{}{"tenant": c.Request().Host}
+	})
+	_, c := newRequestContext("http://acme.example.com/home")
+	buf := new(bytes.Buffer)
+
+	_ = renderer.Render(buf, "request-view", nil, c)
+
+	if buf.String() != "|acme.example.com" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_TemplatesNotReadingRequest_SkipRequestValues(t *testing.T) {
+	calls := 0
+	renderer := newRequestRenderer("<p>{{name}}</p>", func(c echo.Context) map[string]interface{} {
+		calls++
+		return map[string]interface{}{}
+	})
+	_, c := newRequestContext("/home")
+
+	_ = renderer.Render(new(bytes.Buffer), "request-view", nil, c)
+	_ = renderer.Render(new(bytes.Buffer), "request-view", map[string]interface{}{handlebars.LayoutKey: "request-layout"}, c)
+
+	if calls != 1 {
+		t.Errorf("Expected request values built only for the layout reading them, got %d calls", calls)
+	}
+}
+
+func TestHandlebarsRendererRender_RouteAddedAfterRender_IsNamedAfterReset(t *testing.T) {
+	renderer := newRequestRenderer("{{@request.route}}", nil)
+	e, c := newRequestContext("/users")
+	e.GET("/users", func(echo.Context) error { return nil }).Name = "users.index"
+	c.SetPath("/users")
+	_ = renderer.Render(new(bytes.Buffer), "request-view", nil, c)
+	e.GET("/teams", func(echo.Context) error { return nil }).Name = "teams.index"
+	c.SetPath("/teams")
+	before := new(bytes.Buffer)
+	after := new(bytes.Buffer)
+
+	_ = renderer.Render(before, "request-view", nil, c)
+	renderer.ResetRouteNames()
+	_ = renderer.Render(after, "request-view", nil, c)
+
+	if before.String() != "" || after.String() != "teams.index" {
+		t.Errorf("Expected the route named only after resetting, got %q and %q", before.String(), after.String())
+	}
+}
+
+func TestHandlebarsRendererRender_DifferentEcho_IsIndexedSeparately(t *testing.T) {
+	renderer := newRequestRenderer("{{@request.route}}", nil)
+	first, firstContext := newRequestContext("/users")
+	first.GET("/users", func(echo.Context) error { return nil }).Name = "users.index"
+	firstContext.SetPath("/users")
+	second, secondContext := newRequestContext("/users")
+	second.GET("/users", func(echo.Context) error { return nil }).Name = "admin.users"
+	secondContext.SetPath("/users")
+	firstBuf := new(bytes.Buffer)
+	secondBuf := new(bytes.Buffer)
+
+	_ = renderer.Render(firstBuf, "request-view", nil, firstContext)
+	_ = renderer.Render(secondBuf, "request-view", nil, secondContext)
+
+	if firstBuf.String() != "users.index" || secondBuf.String() != "admin.users" {
+		t.Errorf("Expected each Echo's route name, got %q and %q", firstBuf.String(), secondBuf.String())
+	}
+}
+
+func TestHandlebarsRendererRender_CSRFKey_ReadsTokenFromKey(t *testing.T) {
+	viewGatherer := NewMockTemplateGatherer()
+	viewGatherer.AddTemplate(echorend.RawTemplateData{TemplateName: "form", TemplateData: "{{@request.csrf}}"})
+	renderer := handlebars.NewHandlebarsRendererWithConfig(handlebars.HandlebarsRendererConfig{
+		ViewGatherer: viewGatherer,
+		CSRFKey:      "_csrf",
+	})
+	renderer.MustSetup()
+	_, c := newRequestContext("/form")
+	c.Set("_csrf", "token-2")
+	buf := new(bytes.Buffer)
+
+	_ = renderer.Render(buf, "form", nil, c)
+
+	if buf.String() != "token-2" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
+
+func TestDefaultCSRFKey_MatchesEchoCSRFMiddleware(t *testing.T) {
+	if handlebars.DefaultCSRFKey != middleware.DefaultCSRFConfig.ContextKey {
+		t.Errorf("Expected %s, got %s", middleware.DefaultCSRFConfig.ContextKey, handlebars.DefaultCSRFKey)
+	}
+}
+
+func TestHandlebarsRendererRender_NoContext_RendersEmptyRequestValues(t *testing.T) {
+	renderer := newRequestRenderer("[{{@request.path}}]", nil)
+	buf := new(bytes.Buffer)
+
+	err := renderer.Render(buf, "request-view", nil, nil)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "[]" {
+		t.Errorf("Unexpected output %s", buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+package handlebars
+
+import "sort"
+
+// TemplateInfo describes a template of the renderer's current template set.
+type TemplateInfo struct {
+	Name string
+	// Role is "view", "partial" or "layout".
+	Role     string
+	Source   string
+	Gatherer string
+	Hash     string
+}
+
+// Templates returns every view, partial and layout of the current template set, sorted by role and then name.
+func (r *HandlebarsRenderer) Templates() []TemplateInfo {
+	templates := r.current().sorted()
+	infos := make([]TemplateInfo, 0, len(templates))
+	for _, entry := range templates {
+		infos = append(infos, TemplateInfo{
+			Name:     entry.name,
+			Role:     entry.tmpl.role.String(),
+			Source:   entry.tmpl.raw.Source,
+			Gatherer: entry.tmpl.raw.Gatherer,
+			Hash:     entry.tmpl.raw.Hash,
+		})
+	}
+	return infos
+}
+
+type namedTemplate struct {
+	name string
+	tmpl *compiledTemplate
+}
+
+// sorted returns every view, partial and layout of the set, sorted by role and then name.
+func (s *templateSet) sorted() []namedTemplate {
+	templates := make([]namedTemplate, 0, len(s.templates)+len(s.layouts))
+	for name, tmpl := range s.templates {
+		if tmpl.role == roleView {
+			templates = append(templates, namedTemplate{name: name, tmpl: tmpl})
+		}
+	}
+	for name, tmpl := range s.partials {
+		templates = append(templates, namedTemplate{name: name, tmpl: tmpl})
+	}
+	for name, tmpl := range s.layouts {
+		templates = append(templates, namedTemplate{name: name, tmpl: tmpl})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].tmpl.role != templates[j].tmpl.role {
+			return templates[i].tmpl.role < templates[j].tmpl.role
+		}
+		return templates[i].name < templates[j].name
+	})
+	return templates
+}